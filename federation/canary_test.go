@@ -0,0 +1,95 @@
+package federation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRegisterCanaryRejectsRegressingSwap guards RegisterCanary's core
+// promise: a candidate planner that would break a persisted query the
+// current planner still answers is rejected outright, leaving the
+// previously-adopted planner (and its service coverage) in place.
+func TestRegisterCanaryRejectsRegressingSwap(t *testing.T) {
+	ctx := context.Background()
+
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	e, err := NewExecutor(ctx, execs)
+	require.NoError(t, err)
+	defer e.Close()
+
+	require.NoError(t, e.RegisterCanary("s2root-canary", `{ s2root }`))
+
+	// A planner that only ever saw schema1 has no way to resolve s2root, so
+	// adopting it would break the canary.
+	broken, err := planFromExecutors(ctx, map[string]*Executor{
+		"schema1": execs["schema1"],
+	}, nil)
+	require.NoError(t, err)
+
+	before := e.SchemaGeneration()
+	snapshot, diags := e.adoptPlannerChecked(broken)
+	require.NotEmpty(t, diags)
+	assert.Equal(t, SeverityError, diags[0].Severity)
+
+	// The rejected candidate's would-be generation is recorded, but the
+	// executor keeps serving the one it already had.
+	assert.Equal(t, before, snapshot.generation)
+	assert.Equal(t, before, e.SchemaGeneration())
+
+	rejected, ok := e.LastRejectedGeneration()
+	require.True(t, ok)
+	assert.Equal(t, before+1, rejected.Generation)
+	assert.Contains(t, rejected.Canaries, "s2root-canary")
+
+	runAndValidateQueryResults(t, ctx, e, `{ s2root }`, `{ "s2root": "hello" }`)
+}
+
+// TestRollbackRevertsToPreviousSnapshot guards Rollback's history-stack
+// behavior: it reverts to the planner generation adopted immediately before
+// the current one, bumps the generation counter rather than reusing the old
+// number, and errors once there's nothing left to revert to.
+func TestRollbackRevertsToPreviousSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	e, err := NewExecutor(ctx, execs)
+	require.NoError(t, err)
+	defer e.Close()
+
+	firstGen := e.SchemaGeneration()
+
+	_, err = e.Rollback()
+	require.Error(t, err)
+
+	planner, err := planFromExecutors(ctx, execs, nil)
+	require.NoError(t, err)
+	snapshot, diags := e.adoptPlannerChecked(planner)
+	require.Empty(t, diags)
+	require.Equal(t, firstGen+1, snapshot.generation)
+
+	reverted, err := e.Rollback()
+	require.NoError(t, err)
+	assert.Equal(t, firstGen+2, reverted.generation)
+	assert.NotEqual(t, firstGen+1, e.SchemaGeneration())
+	assert.Equal(t, firstGen+2, e.SchemaGeneration())
+
+	runAndValidateQueryResults(t, ctx, e, `{ s2root }`, `{ "s2root": "hello" }`)
+
+	// Nothing left in history to revert to a second time.
+	_, err = e.Rollback()
+	require.Error(t, err)
+}
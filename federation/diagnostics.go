@@ -0,0 +1,145 @@
+package federation
+
+import (
+	"encoding/json"
+
+	"github.com/samsarahq/go/oops"
+)
+
+// Severity distinguishes diagnostics that should take a service out of the
+// merged schema (Error) from ones that are informational (Warning).
+type Severity int
+
+const (
+	SeverityWarning Severity = iota
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// The sync phases a SyncDiagnostic can be attributed to.
+const (
+	PhaseFetch   = "fetch"
+	PhaseParse   = "parse"
+	PhaseConvert = "convert"
+	PhaseMerge   = "merge"
+)
+
+// SyncDiagnostic describes one thing that happened (good or bad) while
+// syncing a federated service's schema.
+type SyncDiagnostic struct {
+	Severity Severity
+	Service  string
+	Phase    string
+	Summary  string
+	Detail   string
+}
+
+// SyncDiagnostics accumulates every SyncDiagnostic produced by a single
+// sync pass, across every service, instead of a SchemaSyncer bailing out on
+// the first bad one.
+type SyncDiagnostics []SyncDiagnostic
+
+// HasErrors reports whether any diagnostic in the set is Error severity.
+func (d SyncDiagnostics) HasErrors() bool {
+	for _, diag := range d {
+		if diag.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagSink receives Warning-severity diagnostics as a sync pass produces
+// them, so operators can wire schema sync health into logs, metrics, or
+// alerts without changing the sync path itself.
+type DiagSink interface {
+	HandleDiagnostic(SyncDiagnostic)
+}
+
+// collectSchemas runs fetchOne for every service, turning a fetch or parse
+// failure into an Error diagnostic instead of aborting the whole sync. A
+// service that fails is simply omitted from the returned map (and so from
+// the merged schema) until the next sync succeeds for it.
+func collectSchemas(services []string, fetchOne func(service string) ([]byte, error)) (map[string]*introspectionQueryResult, SyncDiagnostics) {
+	schemas := make(map[string]*introspectionQueryResult, len(services))
+	var diags SyncDiagnostics
+
+	for _, service := range services {
+		raw, err := fetchOne(service)
+		if err != nil {
+			diags = append(diags, SyncDiagnostic{
+				Severity: SeverityError,
+				Service:  service,
+				Phase:    PhaseFetch,
+				Summary:  "failed to fetch schema",
+				Detail:   err.Error(),
+			})
+			continue
+		}
+
+		var iq introspectionQueryResult
+		if err := json.Unmarshal(raw, &iq); err != nil {
+			diags = append(diags, SyncDiagnostic{
+				Severity: SeverityError,
+				Service:  service,
+				Phase:    PhaseParse,
+				Summary:  "failed to parse introspection result",
+				Detail:   err.Error(),
+			})
+			continue
+		}
+
+		schemas[service] = &iq
+	}
+
+	return schemas, diags
+}
+
+// planFromSchemas runs the convert+merge phase over schemas (as already
+// collected by collectSchemas) and appends an Error diagnostic on failure
+// (Merge phase when schemas is empty, Convert phase otherwise), rather than
+// only returning an error.
+func planFromSchemas(schemas map[string]*introspectionQueryResult, selector ServiceSelector, diags SyncDiagnostics) (*Planner, SyncDiagnostics, error) {
+	if len(schemas) == 0 {
+		diags = append(diags, SyncDiagnostic{
+			Severity: SeverityError,
+			Phase:    PhaseMerge,
+			Summary:  "no services parsed cleanly; keeping the previous planner",
+		})
+		return nil, diags, oops.Errorf("no services parsed cleanly")
+	}
+
+	types, err := convertSchema(schemas)
+	if err != nil {
+		diags = append(diags, SyncDiagnostic{
+			Severity: SeverityError,
+			Phase:    PhaseConvert,
+			Summary:  "failed to convert merged schema",
+			Detail:   err.Error(),
+		})
+		return nil, diags, oops.Wrapf(err, "converting schemas")
+	}
+
+	planner, err := NewPlanner(types, selector)
+	if err != nil {
+		diags = append(diags, SyncDiagnostic{
+			Severity: SeverityError,
+			Phase:    PhaseConvert,
+			Summary:  "failed to build planner",
+			Detail:   err.Error(),
+		})
+		return nil, diags, err
+	}
+
+	return planner, diags, nil
+}
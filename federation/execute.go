@@ -0,0 +1,276 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/graphql"
+)
+
+// federationFieldPrefix names the synthetic Query field a leaf schema must
+// expose per federated type so another service's Plan can refetch
+// instances by the key their own "__federation" field reported: a type
+// named Foo is refetched through a root field named
+// "__federation_Foo(keys: [String!]!): [Foo]", returning one (possibly
+// nil) result per key, in the same order.
+const federationFieldPrefix = "__federation_"
+
+// Execute plans selectionSet against the executor's current schema, runs
+// every resulting Plan against the service (local or peer) that owns it,
+// and splices the results back together into the single JSON-shaped value
+// a caller would expect from running selectionSet against one schema.
+func (e *Executor) Execute(ctx context.Context, selectionSet *graphql.SelectionSet) (interface{}, error) {
+	plan, err := e.Plan(selectionSet)
+	if err != nil {
+		return nil, oops.Wrapf(err, "planning query")
+	}
+
+	root := make(map[string]interface{})
+	if err := e.runPlans(ctx, root, plan.After); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// runPlans executes every plan in plans. A plan with a nil PathStep is one
+// of the synthetic query root's own per-service Plans and its fields merge
+// straight into root; any other plan's PathStep locates the object(s)
+// inside root it refines, and its Service is asked to resolve its
+// SelectionSet for exactly those objects, keyed by each one's
+// "__federation" value.
+func (e *Executor) runPlans(ctx context.Context, root map[string]interface{}, plans []*Plan) error {
+	for _, plan := range plans {
+		if plan.PathStep == nil {
+			value, err := e.runRootPlan(ctx, plan)
+			if err != nil {
+				return oops.Wrapf(err, "executing plan for service %s", plan.Service)
+			}
+			mergeFields(root, value)
+			if err := e.runPlans(ctx, root, plan.After); err != nil {
+				return err
+			}
+			continue
+		}
+
+		targets, err := resolvePath(root, plan.PathStep)
+		if err != nil {
+			return oops.Wrapf(err, "resolving path for service %s", plan.Service)
+		}
+		if len(targets) == 0 {
+			continue
+		}
+
+		keys := make([]string, len(targets))
+		for i, target := range targets {
+			key, _ := target[federationKey].(string)
+			keys[i] = key
+		}
+
+		results, err := e.runKeyedPlan(ctx, plan, keys)
+		if err != nil {
+			return oops.Wrapf(err, "executing plan for service %s", plan.Service)
+		}
+		if len(results) != len(targets) {
+			return oops.Errorf("service %s returned %d results for %d keys", plan.Service, len(results), len(targets))
+		}
+
+		for i, target := range targets {
+			if results[i] == nil {
+				continue
+			}
+			mergeFields(target, results[i])
+			if err := e.runPlans(ctx, target, plan.After); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runRootPlan executes a top-level (PathStep == nil) Plan, whose
+// SelectionSet applies directly to the owning service's own Query type.
+func (e *Executor) runRootPlan(ctx context.Context, plan *Plan) (map[string]interface{}, error) {
+	child, ok := e.Executors[plan.Service]
+	if !ok {
+		return nil, oops.Errorf("unknown service %q", plan.Service)
+	}
+	if child.peer != nil {
+		value, err := child.peer.Fetch(ctx, plan.SelectionSet)
+		if err != nil {
+			return nil, err
+		}
+		return toObject(value)
+	}
+
+	value, err := graphql.Execute(ctx, child.schema, &graphql.Query{SelectionSet: plan.SelectionSet})
+	if err != nil {
+		return nil, err
+	}
+	return toObject(value)
+}
+
+// runKeyedPlan asks plan.Service to resolve plan.SelectionSet for the
+// objects identified by keys, via that service's
+// "__federation_<plan.Type>" root field, returning one (possibly nil)
+// result per key in the same order.
+func (e *Executor) runKeyedPlan(ctx context.Context, plan *Plan, keys []string) ([]map[string]interface{}, error) {
+	child, ok := e.Executors[plan.Service]
+	if !ok {
+		return nil, oops.Errorf("unknown service %q", plan.Service)
+	}
+
+	fieldName := federationFieldPrefix + plan.Type
+	wrapped := &graphql.SelectionSet{
+		Selections: []*graphql.Selection{{
+			Name:         fieldName,
+			Alias:        fieldName,
+			Args:         map[string]interface{}{"keys": keys},
+			SelectionSet: plan.SelectionSet,
+		}},
+	}
+
+	var value interface{}
+	if child.peer != nil {
+		fetched, err := child.peer.Fetch(ctx, wrapped)
+		if err != nil {
+			return nil, err
+		}
+		value = fetched
+	} else {
+		executed, err := graphql.Execute(ctx, child.schema, &graphql.Query{SelectionSet: wrapped})
+		if err != nil {
+			return nil, err
+		}
+		value = executed
+	}
+
+	root, err := toObject(value)
+	if err != nil {
+		return nil, err
+	}
+	list, _ := root[fieldName].([]interface{})
+	if len(list) != len(keys) {
+		return nil, oops.Errorf("expected %d results from %s, got %d", len(keys), fieldName, len(list))
+	}
+
+	results := make([]map[string]interface{}, len(list))
+	for i, item := range list {
+		if item == nil {
+			continue
+		}
+		obj, err := toObject(item)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = obj
+	}
+	return results, nil
+}
+
+// toObject normalizes a graphql.Execute (or peer Fetch) result into
+// map[string]interface{}, round-tripping through JSON when it isn't
+// already shaped that way (a peer's Fetch, for instance, already decodes
+// its own JSON response, while graphql.Execute's native result type may
+// differ).
+func toObject(value interface{}) (map[string]interface{}, error) {
+	if obj, ok := value.(map[string]interface{}); ok {
+		return obj, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, oops.Wrapf(err, "normalizing result")
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, oops.Wrapf(err, "normalizing result")
+	}
+	return obj, nil
+}
+
+// mergeFields copies every field of src into dst (typically a freshly
+// fetched plan result being spliced into the object its Plan was planned
+// against), then removes the planner's own bookkeeping fields, which have
+// served their purpose once the plan they asked for has actually run.
+func mergeFields(dst, src map[string]interface{}) {
+	for k, v := range src {
+		dst[k] = v
+	}
+	delete(dst, federationKey)
+	delete(dst, typenameKey)
+}
+
+// resolvePath walks root following path and returns every object
+// (map[string]interface{}) found at that position, expanding through lists
+// transparently so a Plan whose path crosses a list field splices into
+// every element.
+func resolvePath(root map[string]interface{}, path []PathStep) ([]map[string]interface{}, error) {
+	current := []interface{}{root}
+
+	for _, step := range path {
+		var next []interface{}
+		for _, c := range current {
+			expanded, err := stepInto(c, step)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, expanded...)
+		}
+		current = next
+	}
+
+	targets := make([]map[string]interface{}, 0, len(current))
+	for _, c := range current {
+		switch v := c.(type) {
+		case map[string]interface{}:
+			targets = append(targets, v)
+		case nil:
+			// A null field along the path (e.g. an optional object that
+			// resolved to nil) has nothing to splice into; skip it.
+		default:
+			return nil, oops.Errorf("expected an object at path step %q, got %T", path[len(path)-1].Name, c)
+		}
+	}
+	return targets, nil
+}
+
+// stepInto applies a single PathStep to value, which may itself be a list
+// (each element is stepped into independently) or a single object.
+func stepInto(value interface{}, step PathStep) ([]interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		var out []interface{}
+		for _, elem := range v {
+			next, err := stepInto(elem, step)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, next...)
+		}
+		return out, nil
+
+	case map[string]interface{}:
+		switch step.Kind {
+		case KindField:
+			return []interface{}{v[step.Name]}, nil
+		case KindType:
+			// A KindType step only applies to objects that actually resolved
+			// to that fragment's concrete type (the planner always injects
+			// __typename alongside any KindType step, precisely so this can
+			// be checked); an object of a different type has nothing for
+			// this branch's plan to splice in, so it's dropped rather than
+			// spliced into the wrong branch's result.
+			if typeName, _ := v[typenameKey].(string); typeName != step.Name {
+				return nil, nil
+			}
+			return []interface{}{v}, nil
+		}
+		return nil, oops.Errorf("unknown path step kind")
+
+	case nil:
+		return []interface{}{nil}, nil
+
+	default:
+		return nil, oops.Errorf("cannot step %q into %T", step.Name, value)
+	}
+}
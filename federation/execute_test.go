@@ -0,0 +1,39 @@
+package federation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExecutePolymorphicFragmentSplicing guards against splicing a
+// fragment's plan into an object that didn't actually resolve to that
+// fragment's type: s1both(bar: true) resolves to a Bar, so only the "...
+// on Bar" branch's fields (including the cross-service s1baz field) should
+// appear in the result, never the "... on Foo" branch's (including its own
+// cross-service s2ok field).
+func TestExecutePolymorphicFragmentSplicing(t *testing.T) {
+	ctx := context.Background()
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	e, err := NewExecutor(ctx, execs)
+	require.NoError(t, err)
+
+	query := `{
+		s1both(bar: true) {
+			... on Foo { name s2ok }
+			... on Bar { id s1baz }
+		}
+	}`
+	expected := `{
+		"s1both": { "id": "foo-1", "s1baz": "baz-foo-1" }
+	}`
+
+	runAndValidateQueryResults(t, ctx, e, query, expected)
+}
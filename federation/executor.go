@@ -0,0 +1,498 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/samsarahq/thunder/graphql/introspection"
+)
+
+// defaultSchemaSyncIntervalSeconds is used when CustomExecutorArgs is not
+// provided, or it doesn't set SchemaSyncIntervalSeconds.
+const defaultSchemaSyncIntervalSeconds = 30
+
+// defaultSnapshotHistory is used when CustomExecutorArgs doesn't set
+// SnapshotHistory.
+const defaultSnapshotHistory = 5
+
+// SchemaSyncer knows how to produce a Planner for the current state of the
+// federated services. NewExecutor polls FetchPlanner on an interval and
+// swaps in whatever planner it returns. The returned SyncDiagnostics
+// accumulate one entry per service that had trouble; planner is non-nil as
+// long as at least one service synced cleanly, even when err is non-nil for
+// the rest.
+type SchemaSyncer interface {
+	FetchPlanner(ctx context.Context) (*Planner, SyncDiagnostics, error)
+}
+
+// CustomExecutorArgs customizes how an Executor built by NewExecutor keeps
+// its planner in sync with the federated services' schemas.
+type CustomExecutorArgs struct {
+	SchemaSyncer SchemaSyncer
+
+	// SchemaSyncIntervalSeconds is called on every sync tick so operators can
+	// change the interval (e.g. via a feature flag) without restarting the
+	// executor.
+	SchemaSyncIntervalSeconds func(ctx context.Context) int64
+
+	// ServiceSelector overrides schema ownership when resolving conflicts
+	// between services. Only consulted by the push-based (SchemaSubscriber)
+	// sync path; a polling SchemaSyncer bakes its own selector into the
+	// planners it returns from FetchPlanner.
+	ServiceSelector ServiceSelector
+
+	// ReconcilerWorkers bounds how many schema events can be merged into a
+	// new planner concurrently when the SchemaSyncer is a SchemaSubscriber.
+	// Defaults to 1.
+	ReconcilerWorkers int
+
+	// DiagSink, if set, is called with every Warning-severity diagnostic
+	// produced by a sync pass as it happens.
+	DiagSink DiagSink
+
+	// SnapshotHistory bounds how many previously-adopted snapshots Rollback
+	// can revert through. Defaults to 5.
+	SnapshotHistory int
+}
+
+// Executor either executes queries directly against a single schema (a
+// "leaf" executor, as built by makeExecutors for each federated service), or
+// composes a set of Executors into a federated gateway (as built by
+// NewExecutor).
+type Executor struct {
+	// Executors holds one leaf Executor per federated service. It is nil on
+	// leaf executors themselves.
+	Executors map[string]*Executor
+
+	// schema is set on leaf executors: the schema they execute queries
+	// against directly.
+	schema *graphql.Schema
+
+	// peer is set on leaf executors built by NewPeerExecutor: instead of a
+	// local schema, the sub-selection planned for this "service" is
+	// forwarded to a remote gateway.
+	peer PeerExecutor
+
+	// snapshot holds the current *plannerSnapshot. All query-path code loads
+	// it exactly once at entry so a mid-flight sync can never mix a stale
+	// planner with a newer selector. Use loadSnapshot/adoptPlanner rather
+	// than touching this directly.
+	snapshot   atomic.Value
+	generation uint64
+
+	schemaSyncer SchemaSyncer
+	selector     ServiceSelector
+	diagSink     DiagSink
+	cancel       context.CancelFunc
+
+	diagnostics atomic.Value // stores SyncDiagnostics
+
+	// canaryMu guards canaries: persisted queries registered via
+	// RegisterCanary and replayed against every candidate planner before
+	// it's swapped in.
+	canaryMu sync.Mutex
+	canaries []canary
+
+	// swapMu serializes adoptPlannerChecked and Rollback so two concurrent
+	// sync passes (possible with ReconcilerWorkers > 1) can't both read the
+	// same prev snapshot, validate against it, and adopt out of order.
+	swapMu sync.Mutex
+
+	// historyMu guards history, a ring buffer (most recent last, bounded by
+	// maxHistory) of snapshots Rollback can revert to.
+	historyMu  sync.Mutex
+	history    []*plannerSnapshot
+	maxHistory int
+
+	rejected atomic.Value // stores RejectedGeneration
+
+	// The following fields are only used when schemaSyncer is a
+	// SchemaSubscriber: serviceSchemas is the latest known introspection
+	// result per service, kept up to date by applyEvent, and reconcileCh
+	// hands debounced snapshots of it off to the reconciler worker pool.
+	schemaMu       sync.Mutex
+	serviceSchemas map[string]*introspectionQueryResult
+	reconcileCh    chan map[string]*introspectionQueryResult
+}
+
+// NewExecutor builds a federated gateway Executor over the given per-service
+// executors. With no CustomExecutorArgs, schema changes in the underlying
+// services are only picked up by restarting the process; passing a
+// SchemaSyncer enables polling (and, if the syncer also implements
+// SchemaSubscriber, push-based) schema sync.
+func NewExecutor(ctx context.Context, executors map[string]*Executor, customArgs ...*CustomExecutorArgs) (*Executor, error) {
+	e := &Executor{
+		Executors:  executors,
+		maxHistory: defaultSnapshotHistory,
+	}
+
+	var args *CustomExecutorArgs
+	if len(customArgs) > 0 {
+		args = customArgs[0]
+	}
+	if args != nil && args.SnapshotHistory > 0 {
+		e.maxHistory = args.SnapshotHistory
+	}
+
+	if args == nil || args.SchemaSyncer == nil {
+		planner, err := planFromExecutors(ctx, executors, nil)
+		if err != nil {
+			return nil, oops.Wrapf(err, "building initial planner")
+		}
+		e.adoptPlanner(planner)
+		return e, nil
+	}
+
+	e.schemaSyncer = args.SchemaSyncer
+	e.selector = args.ServiceSelector
+	e.diagSink = args.DiagSink
+
+	planner, diags, err := e.schemaSyncer.FetchPlanner(ctx)
+	e.recordDiagnostics(diags)
+	if planner == nil {
+		return nil, oops.Wrapf(err, "fetching initial planner")
+	}
+	e.adoptPlanner(planner)
+
+	syncCtx, cancel := context.WithCancel(ctx)
+	e.cancel = cancel
+
+	if subscriber, ok := e.schemaSyncer.(SchemaSubscriber); ok {
+		initial, diags, err := subscriber.InitialSchemas(ctx)
+		e.recordDiagnostics(diags)
+		if err != nil && len(initial) == 0 {
+			cancel()
+			return nil, oops.Wrapf(err, "fetching initial per-service schemas")
+		}
+
+		events, err := subscriber.Subscribe(syncCtx)
+		if err != nil {
+			cancel()
+			return nil, oops.Wrapf(err, "subscribing to schema events")
+		}
+
+		workers := args.ReconcilerWorkers
+		if workers <= 0 {
+			workers = 1
+		}
+		if initial == nil {
+			initial = make(map[string]*introspectionQueryResult)
+		}
+		e.serviceSchemas = initial
+		e.reconcileCh = make(chan map[string]*introspectionQueryResult, 1)
+		for i := 0; i < workers; i++ {
+			go e.reconcileWorker(syncCtx)
+		}
+
+		go e.runSubscription(syncCtx, events)
+	} else {
+		intervalFunc := args.SchemaSyncIntervalSeconds
+		if intervalFunc == nil {
+			intervalFunc = func(ctx context.Context) int64 { return defaultSchemaSyncIntervalSeconds }
+		}
+		go e.runPoll(syncCtx, intervalFunc)
+	}
+
+	return e, nil
+}
+
+// runPoll periodically calls FetchPlanner and swaps in the result.
+func (e *Executor) runPoll(ctx context.Context, intervalFunc func(ctx context.Context) int64) {
+	for {
+		interval := time.Duration(intervalFunc(ctx)) * time.Second
+		if interval <= 0 {
+			interval = defaultSchemaSyncIntervalSeconds * time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		planner, diags, err := e.schemaSyncer.FetchPlanner(ctx)
+		if planner == nil {
+			// Every service failed; leave the previous planner in place and
+			// let the next tick try again.
+			e.recordDiagnostics(diags)
+			log.Printf("federation: schema sync failed, keeping previous planner: %v", err)
+			continue
+		}
+		_, rejectDiags := e.adoptPlannerChecked(planner)
+		e.recordDiagnostics(append(diags, rejectDiags...))
+	}
+}
+
+// recordDiagnostics stores diags as the executor's latest sync diagnostics
+// and forwards any Warning-severity entries to the configured DiagSink.
+func (e *Executor) recordDiagnostics(diags SyncDiagnostics) {
+	e.diagnostics.Store(diags)
+	if e.diagSink == nil {
+		return
+	}
+	for _, diag := range diags {
+		if diag.Severity == SeverityWarning {
+			e.diagSink.HandleDiagnostic(diag)
+		}
+	}
+}
+
+// LastSyncDiagnostics returns the SyncDiagnostics produced by the most
+// recent sync pass (polling tick or push-based reconcile), so operators can
+// see which backend broke federation without losing the whole gateway.
+func (e *Executor) LastSyncDiagnostics() SyncDiagnostics {
+	v := e.diagnostics.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(SyncDiagnostics)
+}
+
+// adoptPlanner wraps planner in a new plannerSnapshot (tagged with the next
+// generation number) and atomically swaps it in as the executor's current
+// schema state.
+func (e *Executor) adoptPlanner(planner *Planner) *plannerSnapshot {
+	gen := atomic.AddUint64(&e.generation, 1)
+	snapshot := &plannerSnapshot{
+		types:      planner.schema,
+		planner:    planner,
+		selector:   planner.selector,
+		generation: gen,
+	}
+	e.snapshot.Store(snapshot)
+	log.Printf("federation: adopted schema generation %d (%d services)", gen, len(planner.schema.Services))
+	return snapshot
+}
+
+// canary is a persisted query replayed against every candidate planner
+// before it's swapped in, registered via RegisterCanary.
+type canary struct {
+	name         string
+	selectionSet *graphql.SelectionSet
+}
+
+// RegisterCanary adds a named persisted query that guards every future
+// schema swap: if it currently plans cleanly against the executor's schema
+// but would fail (e.g. "unknown field") against a sync pass's candidate
+// planner, the swap is rejected and the previous planner stays in place.
+func (e *Executor) RegisterCanary(name, query string) error {
+	q, err := graphql.Parse(query, nil)
+	if err != nil {
+		return oops.Wrapf(err, "parsing canary %q", name)
+	}
+
+	e.canaryMu.Lock()
+	defer e.canaryMu.Unlock()
+	e.canaries = append(e.canaries, canary{name: name, selectionSet: q.SelectionSet})
+	return nil
+}
+
+// failingCanaries returns the name of every registered canary that plans
+// cleanly against prev but errors against candidate. A canary that's
+// already broken against prev can't regress any further, so it's skipped
+// rather than blocking an unrelated swap forever.
+func (e *Executor) failingCanaries(prev, candidate *Planner) []string {
+	e.canaryMu.Lock()
+	canaries := make([]canary, len(e.canaries))
+	copy(canaries, e.canaries)
+	e.canaryMu.Unlock()
+
+	var failing []string
+	for _, c := range canaries {
+		if prev != nil {
+			if _, err := prev.Plan(c.selectionSet); err != nil {
+				continue
+			}
+		}
+		if _, err := candidate.Plan(c.selectionSet); err != nil {
+			failing = append(failing, c.name)
+		}
+	}
+	return failing
+}
+
+// RejectedGeneration describes a candidate schema generation that was
+// rejected because it broke a registered canary.
+type RejectedGeneration struct {
+	Generation uint64
+	Canaries   []string
+}
+
+// LastRejectedGeneration returns the most recent candidate schema generation
+// rejected by a canary, if any.
+func (e *Executor) LastRejectedGeneration() (RejectedGeneration, bool) {
+	v := e.rejected.Load()
+	if v == nil {
+		return RejectedGeneration{}, false
+	}
+	return v.(RejectedGeneration), true
+}
+
+// adoptPlannerChecked validates planner against every registered canary
+// before adopting it. The first planner an executor ever sees (prev == nil)
+// has nothing to validate against and is always adopted. On rejection, the
+// current snapshot is left in place and the returned SyncDiagnostics records
+// why; on acceptance, the outgoing snapshot is pushed onto history so
+// Rollback can revert to it later.
+func (e *Executor) adoptPlannerChecked(planner *Planner) (*plannerSnapshot, SyncDiagnostics) {
+	e.swapMu.Lock()
+	defer e.swapMu.Unlock()
+
+	prev := e.loadSnapshot()
+	if prev == nil {
+		return e.adoptPlanner(planner), nil
+	}
+
+	if failing := e.failingCanaries(prev.planner, planner); len(failing) > 0 {
+		gen := atomic.LoadUint64(&e.generation) + 1
+		e.rejected.Store(RejectedGeneration{Generation: gen, Canaries: failing})
+		log.Printf("federation: rejected schema generation %d: canaries now fail: %v", gen, failing)
+		return prev, SyncDiagnostics{{
+			Severity: SeverityError,
+			Phase:    PhaseMerge,
+			Summary:  "rejected schema swap: canary queries regressed",
+			Detail:   fmt.Sprintf("failing canaries: %v", failing),
+		}}
+	}
+
+	e.pushHistory(prev)
+	return e.adoptPlanner(planner), nil
+}
+
+// pushHistory appends snapshot to the ring buffer Rollback reverts through,
+// evicting the oldest entry once it grows past maxHistory. The evicted slot
+// is cleared so its (potentially large) merged schema and Planner aren't
+// kept alive by the backing array.
+func (e *Executor) pushHistory(snapshot *plannerSnapshot) {
+	e.historyMu.Lock()
+	defer e.historyMu.Unlock()
+
+	e.history = append(e.history, snapshot)
+	if len(e.history) > e.maxHistory {
+		evict := len(e.history) - e.maxHistory
+		for i := 0; i < evict; i++ {
+			e.history[i] = nil
+		}
+		e.history = e.history[evict:]
+	}
+}
+
+// Rollback reverts to the most recently adopted snapshot before the
+// executor's current one, as a new schema generation (generation numbers
+// always increase, even on rollback, so callers can't mistake it for the
+// generation originally served under that number).
+func (e *Executor) Rollback() (*plannerSnapshot, error) {
+	e.swapMu.Lock()
+	defer e.swapMu.Unlock()
+
+	e.historyMu.Lock()
+	if len(e.history) == 0 {
+		e.historyMu.Unlock()
+		return nil, oops.Errorf("no previous schema snapshot to roll back to")
+	}
+	prev := e.history[len(e.history)-1]
+	e.history[len(e.history)-1] = nil
+	e.history = e.history[:len(e.history)-1]
+	e.historyMu.Unlock()
+
+	snapshot := e.adoptPlanner(prev.planner)
+	log.Printf("federation: rolled back to schema generation %d (now serving as generation %d)", prev.generation, snapshot.generation)
+	return snapshot, nil
+}
+
+// loadSnapshot returns the executor's current plannerSnapshot, or nil if one
+// has never been set.
+func (e *Executor) loadSnapshot() *plannerSnapshot {
+	v := e.snapshot.Load()
+	if v == nil {
+		return nil
+	}
+	return v.(*plannerSnapshot)
+}
+
+// SchemaGeneration returns the generation number of the schema snapshot the
+// executor is currently planning and executing queries against. Callers can
+// log or trace it alongside a query to tell which schema produced a plan.
+func (e *Executor) SchemaGeneration() uint64 {
+	snapshot := e.loadSnapshot()
+	if snapshot == nil {
+		return 0
+	}
+	return snapshot.generation
+}
+
+// Plan splits selectionSet into a tree of per-service Plans using a single,
+// consistent snapshot of the executor's current schema state.
+func (e *Executor) Plan(selectionSet *graphql.SelectionSet) (*Plan, error) {
+	snapshot := e.loadSnapshot()
+	if snapshot == nil {
+		return nil, oops.Errorf("executor has no planner")
+	}
+	return snapshot.planner.Plan(selectionSet)
+}
+
+// Close stops any background schema sync goroutine.
+func (e *Executor) Close() {
+	if e.cancel != nil {
+		e.cancel()
+	}
+}
+
+// planFromExecutors builds a one-shot Planner by introspecting every leaf
+// executor directly, with no syncer involved. It backs NewExecutor when no
+// CustomExecutorArgs is supplied.
+func planFromExecutors(ctx context.Context, executors map[string]*Executor, selector ServiceSelector) (*Planner, error) {
+	schemas := make(map[string]*introspectionQueryResult, len(executors))
+	for service, executor := range executors {
+		result, err := fetchSchema(ctx, executor, nil)
+		if err != nil {
+			return nil, oops.Wrapf(err, "fetching schema for service %s", service)
+		}
+		var iq introspectionQueryResult
+		if err := json.Unmarshal(result.Result, &iq); err != nil {
+			return nil, oops.Wrapf(err, "unmarshaling schema for service %s", service)
+		}
+		schemas[service] = &iq
+	}
+
+	types, err := convertSchema(schemas)
+	if err != nil {
+		return nil, oops.Wrapf(err, "converting schemas")
+	}
+
+	return NewPlanner(types, selector)
+}
+
+// schemaQueryResult wraps the raw JSON response of running the introspection
+// query against a single service.
+type schemaQueryResult struct {
+	Result []byte
+}
+
+// fetchSchema runs the GraphQL introspection query directly against a leaf
+// Executor's schema.
+func fetchSchema(ctx context.Context, e *Executor, variables map[string]interface{}) (*schemaQueryResult, error) {
+	query, err := graphql.Parse(introspection.Query, variables)
+	if err != nil {
+		return nil, oops.Wrapf(err, "parsing introspection query")
+	}
+
+	value, err := graphql.Execute(ctx, e.schema, query)
+	if err != nil {
+		return nil, oops.Wrapf(err, "executing introspection query")
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, oops.Wrapf(err, "marshaling introspection result")
+	}
+
+	return &schemaQueryResult{Result: data}, nil
+}
@@ -0,0 +1,84 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdoptPlannerCheckedConcurrentSwapsAndQueries guards the atomic-swap
+// guarantee snapshot/loadSnapshot/adoptPlanner exist for: a query in
+// flight always sees one complete, self-consistent planner generation, even
+// while many reconciler workers race to adopt new ones concurrently (the
+// ReconcilerWorkers > 1 case). A torn read would show up here as a query
+// failing outright or as a generation number going backwards.
+func TestAdoptPlannerCheckedConcurrentSwapsAndQueries(t *testing.T) {
+	ctx := context.Background()
+
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	e, err := NewExecutor(ctx, execs)
+	require.NoError(t, err)
+	defer e.Close()
+
+	const numSwaps = 20
+	planners := make([]*Planner, 0, numSwaps)
+	for i := 0; i < numSwaps; i++ {
+		schemas := make(map[string]*introspectionQueryResult, len(execs))
+		for service, exec := range execs {
+			result, err := fetchSchema(ctx, exec, nil)
+			require.NoError(t, err)
+			var iq introspectionQueryResult
+			require.NoError(t, json.Unmarshal(result.Result, &iq))
+			schemas[service] = &iq
+		}
+		planner, diags, err := planFromSchemas(schemas, nil, nil)
+		require.NoError(t, err)
+		require.Empty(t, diags)
+		planners = append(planners, planner)
+	}
+
+	var swapWG sync.WaitGroup
+	for _, planner := range planners {
+		planner := planner
+		swapWG.Add(1)
+		go func() {
+			defer swapWG.Done()
+			e.adoptPlannerChecked(planner)
+		}()
+	}
+
+	const numQueries = 50
+	var queryWG sync.WaitGroup
+	errs := make(chan error, numQueries)
+	for i := 0; i < numQueries; i++ {
+		queryWG.Add(1)
+		go func() {
+			defer queryWG.Done()
+			_, err := e.Execute(ctx, mustParse(`{ s2root }`))
+			errs <- err
+		}()
+	}
+
+	swapWG.Wait()
+	queryWG.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	// NewExecutor's own initial adopt is generation 1; every concurrent
+	// adopt here should also have been accepted (no canaries are registered
+	// to reject any of them), so the generation counter must land on
+	// exactly 1+numSwaps, never skipped or duplicated by a torn swap.
+	require.Equal(t, uint64(1+numSwaps), e.SchemaGeneration())
+}
@@ -0,0 +1,146 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/samsarahq/go/oops"
+)
+
+// filePrefix is prepended to a service name to form the file it is synced
+// from, e.g. service "s1" syncs from "<dir>/fileschemasyncers1".
+const filePrefix = "fileschemasyncer"
+
+// WatchingFileSchemaSyncer is a reference SchemaSyncer (and SchemaSubscriber) that
+// reads each federated service's introspection result from a file named
+// "<dir>/fileschemasyncer<service>" and watches dir for changes with
+// fsnotify, pushing a SchemaEvent per add/change/remove instead of making
+// callers poll FetchPlanner.
+type WatchingFileSchemaSyncer struct {
+	dir      string
+	services []string
+
+	serviceSelector ServiceSelector
+}
+
+// NewWatchingFileSchemaSyncer builds a WatchingFileSchemaSyncer over the given services,
+// whose introspection results are expected to live under dir.
+func NewWatchingFileSchemaSyncer(ctx context.Context, dir string, services []string) *WatchingFileSchemaSyncer {
+	return &WatchingFileSchemaSyncer{
+		dir:      dir,
+		services: services,
+	}
+}
+
+// FetchPlanner satisfies SchemaSyncer by reading every service's file,
+// turning a missing or malformed file into a SyncDiagnostic for that
+// service rather than failing the whole sync.
+func (s *WatchingFileSchemaSyncer) FetchPlanner(ctx context.Context) (*Planner, SyncDiagnostics, error) {
+	schemas, diags := collectSchemas(s.services, func(service string) ([]byte, error) {
+		return ioutil.ReadFile(s.path(service))
+	})
+	return planFromSchemas(schemas, s.serviceSelector, diags)
+}
+
+// InitialSchemas satisfies SchemaSubscriber by reading every service's file
+// once up front, the same way FetchPlanner does, so the executor's view of
+// the world isn't empty until the first fsnotify event arrives.
+func (s *WatchingFileSchemaSyncer) InitialSchemas(ctx context.Context) (map[string]*introspectionQueryResult, SyncDiagnostics, error) {
+	schemas, diags := collectSchemas(s.services, func(service string) ([]byte, error) {
+		return ioutil.ReadFile(s.path(service))
+	})
+	return schemas, diags, nil
+}
+
+// Subscribe satisfies SchemaSubscriber by watching dir and translating
+// fsnotify events for known service files into SchemaEvents.
+func (s *WatchingFileSchemaSyncer) Subscribe(ctx context.Context) (<-chan SchemaEvent, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, oops.Wrapf(err, "creating file watcher")
+	}
+	if err := watcher.Add(s.dir); err != nil {
+		watcher.Close()
+		return nil, oops.Wrapf(err, "watching %s", s.dir)
+	}
+
+	events := make(chan SchemaEvent)
+	go func() {
+		defer close(events)
+		defer watcher.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case ev, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				service := s.serviceForPath(ev.Name)
+				if service == "" {
+					continue
+				}
+
+				switch {
+				case ev.Op&fsnotify.Remove != 0:
+					events <- SchemaRemoved{Service: service}
+
+				case ev.Op&(fsnotify.Write|fsnotify.Create) != 0:
+					raw, err := s.readService(service)
+					if err != nil {
+						continue
+					}
+					if ev.Op&fsnotify.Create != 0 {
+						events <- SchemaAdded{Service: service, IntrospectionResult: raw}
+					} else {
+						events <- SchemaChanged{Service: service, IntrospectionResult: raw}
+					}
+				}
+
+			case <-watcher.Errors:
+				// Best-effort: a watch error doesn't invalidate the syncer, it
+				// just means we might miss an update until the next one.
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *WatchingFileSchemaSyncer) path(service string) string {
+	return filepath.Join(s.dir, filePrefix+service)
+}
+
+func (s *WatchingFileSchemaSyncer) serviceForPath(path string) string {
+	base := filepath.Base(path)
+	if !strings.HasPrefix(base, filePrefix) {
+		return ""
+	}
+	name := strings.TrimPrefix(base, filePrefix)
+	for _, service := range s.services {
+		if service == name {
+			return service
+		}
+	}
+	return ""
+}
+
+func (s *WatchingFileSchemaSyncer) readService(service string) ([]byte, error) {
+	data, err := ioutil.ReadFile(s.path(service))
+	if err != nil {
+		return nil, oops.Wrapf(err, "reading schema file for service %s", service)
+	}
+	// Round-trip through introspectionQueryResult to validate the file
+	// before announcing it.
+	var iq introspectionQueryResult
+	if err := json.Unmarshal(data, &iq); err != nil {
+		return nil, oops.Wrapf(err, "unmarshaling schema for service %s", service)
+	}
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package federation
+
+import (
+	"context"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/federation/pluginproto"
+)
+
+// schemaServer implements pluginproto.SchemaPluginServer over a set of local
+// leaf Executors, reusing fetchSchema to answer FetchSchema the same way a
+// SchemaSyncer would introspect them directly.
+type schemaServer struct {
+	pluginproto.UnimplementedSchemaPluginServer
+
+	executors map[string]*Executor
+}
+
+// ServeSchema returns a pluginproto.SchemaPluginServer that answers
+// FetchSchema/WatchSchemas for the given per-service executors, so a
+// federated service (or a registry in front of several of them) can expose
+// its schema to a GRPCSchemaSyncer without the file-drop pattern used in
+// tests.
+func ServeSchema(ctx context.Context, executors map[string]*Executor) pluginproto.SchemaPluginServer {
+	return &schemaServer{executors: executors}
+}
+
+func (s *schemaServer) FetchSchema(ctx context.Context, req *pluginproto.FetchRequest) (*pluginproto.SchemaResponse, error) {
+	targets := s.executors
+	if req.GetService() != "" {
+		executor, ok := s.executors[req.GetService()]
+		if !ok {
+			return nil, oops.Errorf("unknown service %q", req.GetService())
+		}
+		targets = map[string]*Executor{req.GetService(): executor}
+	}
+
+	schemas := make(map[string][]byte, len(targets))
+	for service, executor := range targets {
+		result, err := fetchSchema(ctx, executor, nil)
+		if err != nil {
+			return nil, oops.Wrapf(err, "fetching schema for service %s", service)
+		}
+		schemas[service] = result.Result
+	}
+
+	return &pluginproto.SchemaResponse{Schemas: schemas}, nil
+}
+
+// WatchSchemas sends one SCHEMA_EVENT_KIND_ADDED event per requested service
+// based on its current schema, then blocks until the stream's context is
+// canceled. Local leaf Executors have no hot-reload signal of their own
+// today, so this is the best this reference server can offer; a real
+// federated service backing its Executors with a SchemaSubscriber-aware
+// store can stream real CHANGED/REMOVED events instead.
+func (s *schemaServer) WatchSchemas(req *pluginproto.WatchRequest, stream pluginproto.SchemaPlugin_WatchSchemasServer) error {
+	ctx := stream.Context()
+
+	services := req.GetServices()
+	if len(services) == 0 {
+		for service := range s.executors {
+			services = append(services, service)
+		}
+	}
+
+	for _, service := range services {
+		executor, ok := s.executors[service]
+		if !ok {
+			return oops.Errorf("unknown service %q", service)
+		}
+		result, err := fetchSchema(ctx, executor, nil)
+		if err != nil {
+			return oops.Wrapf(err, "fetching schema for service %s", service)
+		}
+		if err := stream.Send(&pluginproto.SchemaEvent{
+			Service:             service,
+			Kind:                pluginproto.SchemaEventKind_SCHEMA_EVENT_KIND_ADDED,
+			IntrospectionResult: result.Result,
+		}); err != nil {
+			return err
+		}
+	}
+
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,260 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/federation/pluginproto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPCSchemaSyncer is a SchemaSyncer (and SchemaSubscriber) that fetches
+// federated schemas over gRPC from a pluginproto.SchemaPlugin server,
+// instead of relying on the file-drop pattern FileSchemaSyncer uses in
+// tests. It can be pointed at either one endpoint per federated service, or
+// a single registry endpoint that answers on behalf of all of them.
+type GRPCSchemaSyncer struct {
+	// serviceEndpoints maps service name to dial target. Set this, xor
+	// registryEndpoint.
+	serviceEndpoints map[string]string
+	// registryEndpoint is a single endpoint whose FetchSchema/WatchSchemas
+	// responses already cover every federated service.
+	registryEndpoint string
+
+	dialOpts []grpc.DialOption
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewGRPCSchemaSyncer dials one endpoint per federated service.
+func NewGRPCSchemaSyncer(serviceEndpoints map[string]string, dialOpts ...grpc.DialOption) *GRPCSchemaSyncer {
+	return &GRPCSchemaSyncer{
+		serviceEndpoints: serviceEndpoints,
+		dialOpts:         dialOpts,
+		conns:            make(map[string]*grpc.ClientConn),
+	}
+}
+
+// NewGRPCRegistrySchemaSyncer dials a single registry endpoint that answers
+// FetchSchema/WatchSchemas for every federated service.
+func NewGRPCRegistrySchemaSyncer(registryEndpoint string, dialOpts ...grpc.DialOption) *GRPCSchemaSyncer {
+	return &GRPCSchemaSyncer{
+		registryEndpoint: registryEndpoint,
+		dialOpts:         dialOpts,
+		conns:            make(map[string]*grpc.ClientConn),
+	}
+}
+
+// FetchPlanner satisfies SchemaSyncer. An endpoint that can't be dialed or
+// queried turns into a Fetch-phase diagnostic for every service it would
+// have covered, rather than failing the whole sync.
+func (s *GRPCSchemaSyncer) FetchPlanner(ctx context.Context) (*Planner, SyncDiagnostics, error) {
+	schemas, selector, diags := s.fetchAll(ctx)
+	return planFromSchemas(schemas, selector, diags)
+}
+
+// InitialSchemas satisfies SchemaSubscriber by fetching every endpoint's
+// current schemas once, the same way FetchPlanner does.
+func (s *GRPCSchemaSyncer) InitialSchemas(ctx context.Context) (map[string]*introspectionQueryResult, SyncDiagnostics, error) {
+	schemas, _, diags := s.fetchAll(ctx)
+	return schemas, diags, nil
+}
+
+// Subscribe satisfies SchemaSubscriber by opening a WatchSchemas stream
+// against every configured endpoint and fanning their events into one
+// channel.
+func (s *GRPCSchemaSyncer) Subscribe(ctx context.Context) (<-chan SchemaEvent, error) {
+	endpoints := s.endpoints()
+
+	events := make(chan SchemaEvent)
+	var wg sync.WaitGroup
+
+	for _, endpoint := range endpoints {
+		client, err := s.client(endpoint)
+		if err != nil {
+			return nil, oops.Wrapf(err, "dialing %s", endpoint)
+		}
+
+		stream, err := client.WatchSchemas(ctx, &pluginproto.WatchRequest{})
+		if err != nil {
+			return nil, oops.Wrapf(err, "watching schemas on %s", endpoint)
+		}
+
+		wg.Add(1)
+		go func(stream pluginproto.SchemaPlugin_WatchSchemasClient) {
+			defer wg.Done()
+			for {
+				event, err := stream.Recv()
+				if err != nil {
+					return
+				}
+				translated, ok := translateEvent(event)
+				if !ok {
+					continue
+				}
+				select {
+				case events <- translated:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(stream)
+	}
+
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	return events, nil
+}
+
+func translateEvent(event *pluginproto.SchemaEvent) (SchemaEvent, bool) {
+	switch event.GetKind() {
+	case pluginproto.SchemaEventKind_SCHEMA_EVENT_KIND_ADDED:
+		return SchemaAdded{Service: event.GetService(), IntrospectionResult: event.GetIntrospectionResult()}, true
+	case pluginproto.SchemaEventKind_SCHEMA_EVENT_KIND_CHANGED:
+		return SchemaChanged{Service: event.GetService(), IntrospectionResult: event.GetIntrospectionResult()}, true
+	case pluginproto.SchemaEventKind_SCHEMA_EVENT_KIND_REMOVED:
+		return SchemaRemoved{Service: event.GetService()}, true
+	default:
+		return nil, false
+	}
+}
+
+func (s *GRPCSchemaSyncer) endpoints() []string {
+	if s.registryEndpoint != "" {
+		return []string{s.registryEndpoint}
+	}
+	endpoints := make([]string, 0, len(s.serviceEndpoints))
+	for _, endpoint := range s.serviceEndpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+// fetchAll queries every configured endpoint and accumulates a diagnostic,
+// rather than aborting, for anything that goes wrong along the way: a dial
+// or FetchSchema failure is attributed to every service the endpoint was
+// expected to cover (the endpoint's own name for a registry, since the set
+// of services it covers isn't known until it answers), and a per-service
+// unmarshal failure within an otherwise-successful response is attributed
+// to that service alone.
+func (s *GRPCSchemaSyncer) fetchAll(ctx context.Context) (map[string]*introspectionQueryResult, ServiceSelector, SyncDiagnostics) {
+	schemas := make(map[string]*introspectionQueryResult)
+	var overrides []*pluginproto.ServiceSelectorConfig_Override
+	var diags SyncDiagnostics
+
+	for endpoint, services := range s.endpointServices() {
+		client, err := s.client(endpoint)
+		if err != nil {
+			diags = append(diags, endpointDiagnostics(endpoint, services, "failed to dial endpoint", err)...)
+			continue
+		}
+
+		resp, err := client.FetchSchema(ctx, &pluginproto.FetchRequest{})
+		if err != nil {
+			diags = append(diags, endpointDiagnostics(endpoint, services, "failed to fetch schema", err)...)
+			continue
+		}
+
+		for service, raw := range resp.GetSchemas() {
+			var iq introspectionQueryResult
+			if err := json.Unmarshal(raw, &iq); err != nil {
+				diags = append(diags, SyncDiagnostic{
+					Severity: SeverityError,
+					Service:  service,
+					Phase:    PhaseParse,
+					Summary:  "failed to unmarshal schema from endpoint",
+					Detail:   err.Error(),
+				})
+				continue
+			}
+			schemas[service] = &iq
+		}
+
+		if resp.GetServiceSelector() != nil {
+			overrides = append(overrides, resp.GetServiceSelector().GetOverrides()...)
+		}
+	}
+
+	return schemas, selectorFromOverrides(overrides), diags
+}
+
+// endpointServices maps each endpoint to dial to the services it's expected
+// to cover: itself for a registry endpoint (whose service list isn't known
+// until it responds), or the single service it's dedicated to otherwise.
+func (s *GRPCSchemaSyncer) endpointServices() map[string][]string {
+	if s.registryEndpoint != "" {
+		return map[string][]string{s.registryEndpoint: {s.registryEndpoint}}
+	}
+	byEndpoint := make(map[string][]string, len(s.serviceEndpoints))
+	for service, endpoint := range s.serviceEndpoints {
+		byEndpoint[endpoint] = append(byEndpoint[endpoint], service)
+	}
+	return byEndpoint
+}
+
+// endpointDiagnostics builds one Fetch-phase Error diagnostic per service an
+// endpoint was expected to cover.
+func endpointDiagnostics(endpoint string, services []string, summary string, err error) SyncDiagnostics {
+	diags := make(SyncDiagnostics, 0, len(services))
+	for _, service := range services {
+		diags = append(diags, SyncDiagnostic{
+			Severity: SeverityError,
+			Service:  service,
+			Phase:    PhaseFetch,
+			Summary:  summary,
+			Detail:   oops.Wrapf(err, "endpoint %s", endpoint).Error(),
+		})
+	}
+	return diags
+}
+
+// selectorFromOverrides builds a ServiceSelector out of the declarative
+// per-field overrides pushed down by a central config service.
+func selectorFromOverrides(overrides []*pluginproto.ServiceSelectorConfig_Override) ServiceSelector {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	byField := make(map[string]map[string]string)
+	for _, override := range overrides {
+		fields, ok := byField[override.GetTypeName()]
+		if !ok {
+			fields = make(map[string]string)
+			byField[override.GetTypeName()] = fields
+		}
+		fields[override.GetFieldName()] = override.GetService()
+	}
+
+	return func(typeName, fieldName string) string {
+		return byField[typeName][fieldName]
+	}
+}
+
+func (s *GRPCSchemaSyncer) client(endpoint string) (pluginproto.SchemaPluginClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if conn, ok := s.conns[endpoint]; ok {
+		return pluginproto.NewSchemaPluginClient(conn), nil
+	}
+
+	dialOpts := s.dialOpts
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.Dial(endpoint, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[endpoint] = conn
+
+	return pluginproto.NewSchemaPluginClient(conn), nil
+}
@@ -0,0 +1,55 @@
+package federation
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/samsarahq/thunder/federation/pluginproto"
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// TestGRPCSchemaSyncerFetchPlanner exercises GRPCSchemaSyncer end-to-end
+// against a real grpc.Server backed by ServeSchema, over an in-memory
+// bufconn listener, guarding against a break anywhere along the
+// FetchSchema wire path (request/response (de)serialization, schemaServer's
+// per-service dispatch, GRPCSchemaSyncer's planFromSchemas wiring).
+func TestGRPCSchemaSyncerFetchPlanner(t *testing.T) {
+	ctx := context.Background()
+
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pluginproto.RegisterSchemaPluginServer(server, ServeSchema(ctx, execs))
+	go server.Serve(lis)
+	defer server.Stop()
+
+	dialer := func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}
+
+	syncer := NewGRPCRegistrySchemaSyncer("bufconn",
+		grpc.WithContextDialer(dialer),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	planner, diags, err := syncer.FetchPlanner(ctx)
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	require.NotNil(t, planner)
+
+	e, err := NewExecutor(ctx, execs, &CustomExecutorArgs{SchemaSyncer: syncer})
+	require.NoError(t, err)
+	defer e.Close()
+
+	runAndValidateQueryResults(t, ctx, e, `{ s2root }`, `{ "s2root": "hello" }`)
+}
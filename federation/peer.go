@@ -0,0 +1,56 @@
+package federation
+
+import (
+	"context"
+
+	"github.com/samsarahq/thunder/graphql"
+)
+
+// PeerExecutor is implemented by a connection to another federation gateway,
+// letting its merged schema be imported into this gateway's planner as a
+// single virtual service: from the Planner's point of view, a PathStep's
+// Service resolves to a peer-backed leaf Executor exactly like it would a
+// local one, and the sub-selection is forwarded over the wire instead of
+// executed in-process. See the federation/peering package for the gRPC
+// implementation.
+type PeerExecutor interface {
+	SchemaSyncer
+
+	// Fetch forwards selectionSet, rooted at the field that resolved to this
+	// peer, to the remote gateway and returns its already JSON-decoded
+	// result for splicing back into the parent response.
+	Fetch(ctx context.Context, selectionSet *graphql.SelectionSet) (interface{}, error)
+}
+
+// NewPeerExecutor builds a leaf Executor backed by a remote peer gateway
+// rather than a local schema, so it can be added to the Executors map passed
+// to NewExecutor. NewExecutor does not call the peer's own SchemaSyncer:
+// without a CustomExecutorArgs SchemaSyncer it builds its planner by running
+// introspection against each executor's local schema, which a peer-backed
+// Executor does not have, so the peer's schema reaches the planner only
+// through a SchemaSyncer given to NewExecutor.
+func NewPeerExecutor(peer PeerExecutor) *Executor {
+	return &Executor{peer: peer}
+}
+
+// NewLocalExecutor builds a leaf Executor backed directly by a local
+// *graphql.Schema, for a federated service whose resolvers live in the same
+// process as the gateway being built (as opposed to NewPeerExecutor, which
+// forwards to a schema living behind a remote connection).
+func NewLocalExecutor(schema *graphql.Schema) *Executor {
+	return &Executor{schema: schema}
+}
+
+// Schema returns the merged graphql.Schema the executor is currently
+// planning against, or nil if it hasn't adopted a planner yet. Exported for
+// introspection and diagnostics; queries themselves should go through
+// Execute, which plans and dispatches to each owning service rather than
+// running directly against this merged schema (which has no resolvers of
+// its own).
+func (e *Executor) Schema() *graphql.Schema {
+	snapshot := e.loadSnapshot()
+	if snapshot == nil || snapshot.types == nil {
+		return nil
+	}
+	return snapshot.types.Schema
+}
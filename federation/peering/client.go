@@ -0,0 +1,69 @@
+package peering
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/federation"
+	"github.com/samsarahq/thunder/federation/peering/peerproto"
+	"github.com/samsarahq/thunder/graphql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// PeerExecutor is a federation.PeerExecutor that forwards sub-selections to
+// another thunder gateway over gRPC. Its schema sync reuses
+// federation.GRPCSchemaSyncer pointed at the same endpoint, since a peer
+// already exposes a pluginproto.SchemaPlugin server alongside PeerQuery (the
+// former from federation.ServeSchema; NewPeerServer serves only PeerQuery).
+type PeerExecutor struct {
+	federation.SchemaSyncer
+
+	endpoint string
+	conn     *grpc.ClientConn
+	client   peerproto.PeerQueryClient
+}
+
+// NewPeerExecutor dials endpoint and returns a PeerExecutor that imports its
+// merged schema (via a federation.GRPCSchemaSyncer against the same
+// endpoint) and forwards queries to it.
+func NewPeerExecutor(endpoint string, dialOpts ...grpc.DialOption) (*PeerExecutor, error) {
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.Dial(endpoint, dialOpts...)
+	if err != nil {
+		return nil, oops.Wrapf(err, "dialing peer %s", endpoint)
+	}
+
+	return &PeerExecutor{
+		SchemaSyncer: federation.NewGRPCSchemaSyncer(map[string]string{endpoint: endpoint}, dialOpts...),
+		endpoint:     endpoint,
+		conn:         conn,
+		client:       peerproto.NewPeerQueryClient(conn),
+	}, nil
+}
+
+// Fetch satisfies federation.PeerExecutor by forwarding selectionSet to the
+// peer's PeerQuery service and decoding its JSON result.
+func (p *PeerExecutor) Fetch(ctx context.Context, selectionSet *graphql.SelectionSet) (interface{}, error) {
+	resp, err := p.client.Execute(ctx, &peerproto.ExecuteRequest{
+		SelectionSet: toProtoSelectionSet(selectionSet),
+	})
+	if err != nil {
+		return nil, oops.Wrapf(err, "executing query against peer %s", p.endpoint)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(resp.GetResult(), &value); err != nil {
+		return nil, oops.Wrapf(err, "unmarshaling result from peer %s", p.endpoint)
+	}
+	return value, nil
+}
+
+// Close releases the gRPC connection to the peer.
+func (p *PeerExecutor) Close() error {
+	return p.conn.Close()
+}
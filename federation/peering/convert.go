@@ -0,0 +1,102 @@
+package peering
+
+import (
+	"encoding/json"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/federation/peering/peerproto"
+	"github.com/samsarahq/thunder/graphql"
+)
+
+// toProtoSelectionSet converts a planned *graphql.SelectionSet into its wire
+// form for ExecuteRequest: every part a forwarded Plan can depend on (alias,
+// args, fragments, nesting) round-trips, since runKeyedPlan's keyed
+// refetches only work because their args survive the hop, aliased fields
+// only land under the right result key because their alias survives, and
+// polymorphic fields only keep their fragment branches because fragments
+// survive.
+func toProtoSelectionSet(selectionSet *graphql.SelectionSet) *peerproto.SelectionSet {
+	if selectionSet == nil {
+		return nil
+	}
+
+	selections := make([]*peerproto.Selection, 0, len(selectionSet.Selections))
+	for _, selection := range selectionSet.Selections {
+		selections = append(selections, toProtoSelection(selection))
+	}
+
+	fragments := make([]*peerproto.Fragment, 0, len(selectionSet.Fragments))
+	for _, fragment := range selectionSet.Fragments {
+		fragments = append(fragments, &peerproto.Fragment{
+			On:           fragment.On,
+			SelectionSet: toProtoSelectionSet(fragment.SelectionSet),
+		})
+	}
+
+	return &peerproto.SelectionSet{Selections: selections, Fragments: fragments}
+}
+
+func toProtoSelection(selection *graphql.Selection) *peerproto.Selection {
+	proto := &peerproto.Selection{
+		Name:         selection.Name,
+		Alias:        selection.Alias,
+		SelectionSet: toProtoSelectionSet(selection.SelectionSet),
+	}
+	if len(selection.Args) > 0 {
+		// Args round-trip as JSON rather than a typed proto field since a
+		// selection's args (e.g. runKeyedPlan's "keys", or an arbitrary
+		// object literal argument) can be shaped however the query likes.
+		argsJSON, err := json.Marshal(selection.Args)
+		if err == nil {
+			proto.ArgsJson = argsJSON
+		}
+	}
+	return proto
+}
+
+// fromProtoSelectionSet reconstructs a *graphql.SelectionSet from its wire
+// form, the inverse of toProtoSelectionSet.
+func fromProtoSelectionSet(set *peerproto.SelectionSet) (*graphql.SelectionSet, error) {
+	if set == nil {
+		return nil, nil
+	}
+
+	var selections []*graphql.Selection
+	for _, selection := range set.GetSelections() {
+		s, err := fromProtoSelection(selection)
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+	}
+
+	var fragments []*graphql.Fragment
+	for _, fragment := range set.GetFragments() {
+		fragmentSelectionSet, err := fromProtoSelectionSet(fragment.GetSelectionSet())
+		if err != nil {
+			return nil, err
+		}
+		fragments = append(fragments, &graphql.Fragment{On: fragment.GetOn(), SelectionSet: fragmentSelectionSet})
+	}
+
+	return &graphql.SelectionSet{Selections: selections, Fragments: fragments}, nil
+}
+
+func fromProtoSelection(selection *peerproto.Selection) (*graphql.Selection, error) {
+	result := &graphql.Selection{Name: selection.GetName(), Alias: selection.GetAlias()}
+
+	if argsJSON := selection.GetArgsJson(); len(argsJSON) > 0 {
+		var args map[string]interface{}
+		if err := json.Unmarshal(argsJSON, &args); err != nil {
+			return nil, oops.Wrapf(err, "unmarshaling args for selection %q", selection.GetName())
+		}
+		result.Args = args
+	}
+
+	selectionSet, err := fromProtoSelectionSet(selection.GetSelectionSet())
+	if err != nil {
+		return nil, err
+	}
+	result.SelectionSet = selectionSet
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package peering
+
+import (
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSelectionSetRoundTrip guards against the wire form silently dropping
+// alias, args, or fragments: every forwarded Plan depends on one of these
+// (runKeyedPlan's keyed refetches need args, the planner's aliased fields
+// need alias, and polymorphic fields need fragments).
+func TestSelectionSetRoundTrip(t *testing.T) {
+	in := &graphql.SelectionSet{
+		Selections: []*graphql.Selection{
+			{
+				Name:  "__federation_Foo",
+				Alias: "__federation_Foo",
+				Args:  map[string]interface{}{"keys": []interface{}{"foo-1", "foo-2"}},
+				SelectionSet: &graphql.SelectionSet{
+					Selections: []*graphql.Selection{
+						{Name: "s2ok"},
+						{Alias: "a", Name: "s1nest"},
+					},
+				},
+			},
+		},
+		Fragments: []*graphql.Fragment{
+			{
+				On: "Foo",
+				SelectionSet: &graphql.SelectionSet{
+					Selections: []*graphql.Selection{{Name: "name"}},
+				},
+			},
+			{
+				On: "Bar",
+				SelectionSet: &graphql.SelectionSet{
+					Selections: []*graphql.Selection{{Name: "id"}},
+				},
+			},
+		},
+	}
+
+	out, err := fromProtoSelectionSet(toProtoSelectionSet(in))
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
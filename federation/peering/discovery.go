@@ -0,0 +1,78 @@
+package peering
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"strconv"
+
+	"github.com/samsarahq/go/oops"
+)
+
+// Peer is one gateway this process can peer with: a name (used as the
+// virtual service name in the local planner) and a dial target.
+type Peer struct {
+	Name     string
+	Endpoint string
+}
+
+// Discovery is pluggable so peer membership can be backed by whatever an
+// operator already uses to track gateway instances: a static list, a config
+// file reloaded out of band, DNS, or a service registry.
+type Discovery interface {
+	Peers(ctx context.Context) ([]Peer, error)
+}
+
+// StaticDiscovery is a fixed, never-changing set of peers, useful for tests
+// and small deployments.
+type StaticDiscovery []Peer
+
+func (d StaticDiscovery) Peers(ctx context.Context) ([]Peer, error) {
+	return d, nil
+}
+
+// FileDiscovery reads a JSON-encoded []Peer from Path on every call to
+// Peers, so an operator can update peer membership by rewriting the file
+// without restarting the gateway.
+type FileDiscovery struct {
+	Path string
+}
+
+func (d FileDiscovery) Peers(ctx context.Context) ([]Peer, error) {
+	data, err := ioutil.ReadFile(d.Path)
+	if err != nil {
+		return nil, oops.Wrapf(err, "reading peer file %s", d.Path)
+	}
+	var peers []Peer
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return nil, oops.Wrapf(err, "unmarshaling peer file %s", d.Path)
+	}
+	return peers, nil
+}
+
+// DNSDiscovery resolves a single SRV name into one peer per returned
+// target, useful in environments (e.g. Kubernetes headless services) where
+// peer gateways are registered under a shared DNS name instead of a config
+// file or registry.
+type DNSDiscovery struct {
+	// Service, Proto, and Name are passed to net.LookupSRV as-is, e.g.
+	// ("peer", "tcp", "gateway.internal").
+	Service, Proto, Name string
+}
+
+func (d DNSDiscovery) Peers(ctx context.Context) ([]Peer, error) {
+	_, records, err := net.DefaultResolver.LookupSRV(ctx, d.Service, d.Proto, d.Name)
+	if err != nil {
+		return nil, oops.Wrapf(err, "looking up SRV records for %s", d.Name)
+	}
+
+	peers := make([]Peer, 0, len(records))
+	for _, record := range records {
+		peers = append(peers, Peer{
+			Name:     record.Target,
+			Endpoint: net.JoinHostPort(record.Target, strconv.Itoa(int(record.Port))),
+		})
+	}
+	return peers, nil
+}
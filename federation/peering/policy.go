@@ -0,0 +1,45 @@
+package peering
+
+// PeeringPolicy controls which types and fields of a gateway's merged schema
+// are exported to its peers, versus kept private. The default policy
+// exports everything reachable from Query and denies Mutation outright,
+// since a write accepted by one gateway and silently re-routed through
+// another is rarely what an operator wants.
+type PeeringPolicy struct {
+	// DenyMutations excludes the Mutation type from what's exported to
+	// peers. DefaultPeeringPolicy sets it to true; the zero value is false,
+	// which opts a gateway in to serving peer-forwarded mutations.
+	DenyMutations bool
+
+	// ExcludeTypes keeps the named types out of what's exported, even if
+	// they're reachable from Query.
+	ExcludeTypes map[string]bool
+
+	// ExcludeFields keeps individual fields private, keyed "TypeName.field".
+	ExcludeFields map[string]bool
+}
+
+// DefaultPeeringPolicy returns the policy used when NewPeerServer isn't
+// given one explicitly: export everything reachable from Query, deny
+// mutations.
+func DefaultPeeringPolicy() *PeeringPolicy {
+	return &PeeringPolicy{DenyMutations: true}
+}
+
+// Allows reports whether typeName.fieldName may be resolved on behalf of a
+// peer.
+func (p *PeeringPolicy) Allows(typeName, fieldName string) bool {
+	if p == nil {
+		return true
+	}
+	if p.DenyMutations && typeName == "Mutation" {
+		return false
+	}
+	if p.ExcludeTypes[typeName] {
+		return false
+	}
+	if p.ExcludeFields[typeName+"."+fieldName] {
+		return false
+	}
+	return true
+}
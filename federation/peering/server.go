@@ -0,0 +1,61 @@
+package peering
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/federation"
+	"github.com/samsarahq/thunder/federation/peering/peerproto"
+)
+
+// peerServer implements peerproto.PeerQueryServer over a local federation.Executor,
+// executing whatever a peer gateway forwards against the executor's current
+// merged schema, subject to policy.
+type peerServer struct {
+	peerproto.UnimplementedPeerQueryServer
+
+	executor *federation.Executor
+	policy   *PeeringPolicy
+}
+
+// NewPeerServer returns a peerproto.PeerQueryServer that lets other thunder
+// gateways import executor's merged schema as a peer, restricted by policy.
+// A nil policy is equivalent to DefaultPeeringPolicy(). The policy is
+// checked only against the top-level fields of each forwarded selection
+// set, as fields of Query; nested fields and the types they return are not
+// checked.
+func NewPeerServer(executor *federation.Executor, policy *PeeringPolicy) peerproto.PeerQueryServer {
+	if policy == nil {
+		policy = DefaultPeeringPolicy()
+	}
+	return &peerServer{executor: executor, policy: policy}
+}
+
+func (s *peerServer) Execute(ctx context.Context, req *peerproto.ExecuteRequest) (*peerproto.ExecuteResponse, error) {
+	selectionSet, err := fromProtoSelectionSet(req.GetSelectionSet())
+	if err != nil {
+		return nil, oops.Wrapf(err, "decoding selection set forwarded by peer")
+	}
+	if selectionSet == nil {
+		return nil, oops.Errorf("empty selection set")
+	}
+
+	for _, selection := range selectionSet.Selections {
+		if !s.policy.Allows("Query", selection.Name) {
+			return nil, oops.Errorf("field %q is not exported to peers", selection.Name)
+		}
+	}
+
+	value, err := s.executor.Execute(ctx, selectionSet)
+	if err != nil {
+		return nil, oops.Wrapf(err, "executing query forwarded by peer")
+	}
+
+	result, err := json.Marshal(value)
+	if err != nil {
+		return nil, oops.Wrapf(err, "marshaling result")
+	}
+
+	return &peerproto.ExecuteResponse{Result: result}, nil
+}
@@ -0,0 +1,253 @@
+package federation
+
+import (
+	"sort"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/graphql"
+)
+
+// Kind distinguishes the two ways a PathStep can address into a result: by
+// field name, or by concrete type when crossing an interface/union
+// fragment.
+type Kind int
+
+const (
+	KindField Kind = iota
+	KindType
+)
+
+// PathStep is a single hop in the path from the root of a query down to the
+// place where a sub-plan's results get spliced back into its parent.
+type PathStep struct {
+	Kind Kind
+	Name string
+}
+
+// Plan describes a selection set that a single service should execute, plus
+// the sub-plans (After) that depend on its result and must run once it
+// returns. PathStep is relative to the root of Plan's own SelectionSet's
+// result, not to the overall query: a Plan is a self-contained fetch, and
+// its After entries describe where, inside whatever comes back from
+// executing SelectionSet, each child's result should be spliced in.
+type Plan struct {
+	PathStep []PathStep
+	Service  string
+	Type     string
+
+	SelectionSet *graphql.SelectionSet
+	After        []*Plan
+}
+
+// federationKey is the field name the planner injects into a SelectionSet
+// whenever part of it was split off into an After plan, so the executor has
+// something in the parent's result to splice the child's result onto.
+const federationKey = "__federation"
+
+// typenameKey is the field name the planner injects whenever a selection
+// set has type-conditional fragments, so the executor can tell which
+// fragment's fields actually apply to a given result.
+const typenameKey = "__typename"
+
+// Planner splits federated GraphQL queries into a tree of per-service Plans.
+type Planner struct {
+	schema   *convertedSchema
+	selector ServiceSelector
+}
+
+// NewPlanner builds a Planner over a merged schema. selector may be nil, in
+// which case every field resolves to its default (first-declaring) owner.
+func NewPlanner(schema *convertedSchema, selector ServiceSelector) (*Planner, error) {
+	if schema == nil {
+		return nil, oops.Errorf("cannot build a planner over a nil schema")
+	}
+	return &Planner{
+		schema:   schema,
+		selector: selector,
+	}, nil
+}
+
+// resolveService decides which service should resolve typeName.fieldName
+// given that a Plan for currentService is already being built: the
+// ServiceSelector, when set, always wins; otherwise a field already
+// provided by currentService stays there rather than forcing an
+// unnecessary split, falling back to the field's default (first-declaring)
+// owner. currentService == "" (used for the very first, root grouping pass)
+// never matches, so every field resolves to its own owner.
+func (p *Planner) resolveService(currentService, typeName, fieldName string) string {
+	if p.selector != nil {
+		if service := p.selector(typeName, fieldName); service != "" {
+			return service
+		}
+	}
+	if currentService != "" && p.schema.provides(typeName, fieldName, currentService) {
+		return currentService
+	}
+	return p.schema.owner(typeName, fieldName)
+}
+
+// Plan splits selectionSet, rooted at the Query type, into a tree of
+// per-service Plans. The returned Plan is a synthetic root (Service == "")
+// whose After holds one Plan per service needed to answer the top-level
+// selection set.
+func (p *Planner) Plan(selectionSet *graphql.SelectionSet) (*Plan, error) {
+	_, plans, err := p.planSelectionSet("", nil, "Query", selectionSet)
+	if err != nil {
+		return nil, oops.Wrapf(err, "planning selection set")
+	}
+	return &Plan{After: plans}, nil
+}
+
+// fieldKey returns the output key a selection occupies in its result: its
+// alias if it has one, otherwise its field name.
+func fieldKey(selection *graphql.Selection) string {
+	if selection.Alias != "" {
+		return selection.Alias
+	}
+	return selection.Name
+}
+
+// planSelectionSet splits selectionSet (known to apply to typeName) between
+// currentService, whose matching fields are kept inline and recursed into
+// at path, and whichever other services are needed for the rest, each
+// returned as a fully self-contained Plan (already carrying its own further
+// splits). currentService == "" forces every field into its own Plan; this
+// is how the root call produces the top-level per-service Plans.
+//
+// local is nil when selectionSet is nil, and otherwise always non-nil (it
+// may end up holding nothing but a __federation marker).
+func (p *Planner) planSelectionSet(currentService string, path []PathStep, typeName string, selectionSet *graphql.SelectionSet) (*graphql.SelectionSet, []*Plan, error) {
+	if selectionSet == nil {
+		return nil, nil, nil
+	}
+
+	var local []*graphql.Selection
+	var after []*Plan
+
+	groups := newServiceGroups()
+
+	for _, selection := range selectionSet.Selections {
+		resolved := p.resolveService(currentService, typeName, selection.Name)
+		if resolved == "" {
+			return nil, nil, oops.Errorf("unknown field %s on typ %s", selection.Name, typeName)
+		}
+
+		if resolved == currentService && currentService != "" {
+			kept, childAfter, err := p.planField(currentService, path, typeName, selection)
+			if err != nil {
+				return nil, nil, err
+			}
+			local = append(local, kept)
+			after = append(after, childAfter...)
+			continue
+		}
+
+		groups.add(resolved, selection)
+	}
+
+	if groups.len() > 0 {
+		local = append(local, &graphql.Selection{Name: federationKey})
+	}
+
+	for _, service := range groups.order {
+		plan, err := p.buildServicePlan(service, path, typeName, groups.fields[service])
+		if err != nil {
+			return nil, nil, err
+		}
+		after = append(after, plan)
+	}
+
+	fragments := make([]*graphql.Fragment, len(selectionSet.Fragments))
+	copy(fragments, selectionSet.Fragments)
+	sort.Slice(fragments, func(i, j int) bool { return fragments[i].On < fragments[j].On })
+
+	var localFragments []*graphql.Fragment
+	for _, fragment := range fragments {
+		fragSelectionSet, fragAfter, err := p.planSelectionSet(currentService, append(clonePath(path), PathStep{Kind: KindType, Name: fragment.On}), fragment.On, fragment.SelectionSet)
+		if err != nil {
+			return nil, nil, err
+		}
+		localFragments = append(localFragments, &graphql.Fragment{On: fragment.On, SelectionSet: fragSelectionSet})
+		after = append(after, fragAfter...)
+	}
+	if len(selectionSet.Fragments) > 0 {
+		local = append([]*graphql.Selection{{Name: typenameKey}}, local...)
+	}
+
+	return &graphql.SelectionSet{Selections: local, Fragments: localFragments}, after, nil
+}
+
+// planField plans a single field already known to stay with currentService:
+// if it has its own sub-selection, that sub-selection is recursed into at
+// path extended with this field's step, crossing into whatever further
+// services its own fields need. The returned Selection is a shallow copy of
+// selection with only SelectionSet rewritten, so alias/args/directives pass
+// through untouched.
+func (p *Planner) planField(currentService string, path []PathStep, typeName string, selection *graphql.Selection) (*graphql.Selection, []*Plan, error) {
+	if selection.SelectionSet == nil {
+		kept := *selection
+		return &kept, nil, nil
+	}
+
+	childType := p.schema.fieldType(typeName, selection.Name)
+	childPath := append(clonePath(path), PathStep{Kind: KindField, Name: fieldKey(selection)})
+
+	childLocal, childAfter, err := p.planSelectionSet(currentService, childPath, childType, selection.SelectionSet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kept := *selection
+	kept.SelectionSet = childLocal
+	return &kept, childAfter, nil
+}
+
+// buildServicePlan builds the self-contained Plan that resolves fields
+// (all selected on typeName, all belonging to service) from service. path
+// is the position, in whichever Plan is currently being built, where this
+// group's fields live; the new Plan's own recursion starts fresh from
+// there, since its PathStep entries are relative to its own fetch.
+func (p *Planner) buildServicePlan(service string, path []PathStep, typeName string, fields []*graphql.Selection) (*Plan, error) {
+	local, after, err := p.planSelectionSet(service, nil, typeName, &graphql.SelectionSet{Selections: fields})
+	if err != nil {
+		return nil, err
+	}
+	return &Plan{
+		PathStep:     path,
+		Service:      service,
+		Type:         typeName,
+		SelectionSet: local,
+		After:        after,
+	}, nil
+}
+
+// clonePath returns a copy of path so appending to it in one branch (e.g.
+// one field, or one fragment) can never reallocate into and corrupt a
+// sibling branch's slice.
+func clonePath(path []PathStep) []PathStep {
+	out := make([]PathStep, len(path))
+	copy(out, path)
+	return out
+}
+
+// serviceGroups accumulates, in first-seen order, the selections destined
+// for each service other than whichever one is currently being built.
+type serviceGroups struct {
+	order  []string
+	fields map[string][]*graphql.Selection
+}
+
+func newServiceGroups() *serviceGroups {
+	return &serviceGroups{fields: make(map[string][]*graphql.Selection)}
+}
+
+func (g *serviceGroups) add(service string, selection *graphql.Selection) {
+	if _, ok := g.fields[service]; !ok {
+		g.order = append(g.order, service)
+	}
+	g.fields[service] = append(g.fields[service], selection)
+}
+
+func (g *serviceGroups) len() int {
+	return len(g.order)
+}
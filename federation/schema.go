@@ -0,0 +1,173 @@
+package federation
+
+import (
+	"context"
+	"sort"
+
+	"github.com/samsarahq/go/oops"
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/samsarahq/thunder/graphql/introspection"
+)
+
+// ServiceSelector overrides which service resolves a given type/field pair.
+// It is consulted by the planner for every field, whether or not more than
+// one service declares it; returning "" leaves the default owner (the first
+// service, alphabetically, to declare the field) in place.
+type ServiceSelector func(typeName string, fieldName string) string
+
+// convertSchema merges the introspection results of every federated service
+// into a single convertedSchema: a planner-ready map of every type's fields
+// (and who can resolve each one) plus a real, introspectable *graphql.Schema
+// assembled from the same data (its resolvers only return errors; see
+// unresolvedFieldResolve), for introspection and for exposing the merged
+// schema to peers.
+func convertSchema(schemas map[string]*introspectionQueryResult) (*convertedSchema, error) {
+	services := make([]string, 0, len(schemas))
+	for service := range schemas {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	types := make(map[string]*typeInfo)
+	for _, service := range services {
+		iq := schemas[service]
+		if iq == nil {
+			return nil, oops.Errorf("service %s returned a nil introspection result", service)
+		}
+		for _, typ := range iq.Schema.Types {
+			info, ok := types[typ.Name]
+			if !ok {
+				info = &typeInfo{Fields: make(map[string]*fieldInfo)}
+				types[typ.Name] = info
+			}
+			if info.Kind == "" {
+				info.Kind = string(typ.Kind)
+			}
+			if len(info.PossibleTypes) == 0 {
+				for _, possible := range typ.PossibleTypes {
+					info.PossibleTypes = append(info.PossibleTypes, possible.Name)
+				}
+			}
+			for _, field := range typ.Fields {
+				f, ok := info.Fields[field.Name]
+				if !ok {
+					f = &fieldInfo{ReturnType: unwrapTypeName(field.Type)}
+					info.Fields[field.Name] = f
+				}
+				f.Providers = append(f.Providers, service)
+			}
+		}
+	}
+	for _, info := range types {
+		for _, f := range info.Fields {
+			sort.Strings(f.Providers)
+		}
+	}
+
+	schema, err := buildMergedSchema(types)
+	if err != nil {
+		return nil, oops.Wrapf(err, "building merged schema")
+	}
+
+	return &convertedSchema{
+		Schema:   schema,
+		Services: services,
+		types:    types,
+	}, nil
+}
+
+// unwrapTypeName strips NonNull/List wrapping off an introspection type
+// reference and returns the underlying named type, since a selection set is
+// planned against the named type regardless of how a field wraps it.
+func unwrapTypeName(ref *introspection.TypeRef) string {
+	for ref != nil && ref.Name == "" && ref.OfType != nil {
+		ref = ref.OfType
+	}
+	if ref == nil {
+		return ""
+	}
+	return ref.Name
+}
+
+// unresolvedFieldResolve is the Resolve function given to every field of the
+// merged schema. The gateway never executes a query directly against the
+// merged schema: Executor.Execute plans every query into per-service Plans
+// and dispatches each to the owning service's own real schema instead. The
+// merged schema only needs to be structurally complete, so
+// introspection.RunIntrospectionQuery (which walks types and fields, not
+// resolvers) can describe it and peers can treat it as a single service.
+func unresolvedFieldResolve(ctx context.Context, source, args interface{}) (interface{}, error) {
+	return nil, oops.Errorf("merged federation schema has no resolvers; queries must go through Executor.Execute")
+}
+
+// buildMergedSchema assembles a real, introspectable *graphql.Schema out of
+// the merged per-type field metadata: one graphql.Object per OBJECT type,
+// one graphql.Union per UNION/INTERFACE type, with a field per provider-
+// merged fieldInfo pointing back at the right return type.
+func buildMergedSchema(types map[string]*typeInfo) (*graphql.Schema, error) {
+	objects := make(map[string]*graphql.Object)
+	for name, info := range types {
+		if info.Kind != "OBJECT" {
+			continue
+		}
+		objects[name] = &graphql.Object{Name: name, Fields: make(map[string]*graphql.Field)}
+	}
+
+	// Unions (and interfaces, which this merged schema represents the same
+	// way: a set of possible concrete object types) are built next, so
+	// resolveType can already see them by the time it's used to type
+	// object fields below, e.g. a field whose return type is itself a
+	// union.
+	unions := make(map[string]*graphql.Union)
+	for name, info := range types {
+		if info.Kind != "UNION" && info.Kind != "INTERFACE" {
+			continue
+		}
+		possibleTypes := make(map[string]*graphql.Object, len(info.PossibleTypes))
+		for _, possibleName := range info.PossibleTypes {
+			if obj, ok := objects[possibleName]; ok {
+				possibleTypes[possibleName] = obj
+			}
+		}
+		unions[name] = &graphql.Union{Name: name, Types: possibleTypes}
+	}
+
+	resolveType := func(name string) graphql.Type {
+		if obj, ok := objects[name]; ok {
+			return obj
+		}
+		if u, ok := unions[name]; ok {
+			return u
+		}
+		return &graphql.Scalar{Type: name}
+	}
+
+	for name, info := range types {
+		if info.Kind != "OBJECT" {
+			continue
+		}
+		obj := objects[name]
+		for fieldName, f := range info.Fields {
+			obj.Fields[fieldName] = &graphql.Field{
+				Type:    resolveType(f.ReturnType),
+				Resolve: unresolvedFieldResolve,
+			}
+		}
+	}
+
+	var query, mutation *graphql.Object
+	if obj, ok := objects["Query"]; ok {
+		query = obj
+	} else {
+		query = &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{}}
+	}
+	if obj, ok := objects["Mutation"]; ok {
+		mutation = obj
+	}
+
+	schema := &graphql.Schema{Query: query}
+	if mutation != nil {
+		schema.Mutation = mutation
+	}
+	return schema, nil
+}
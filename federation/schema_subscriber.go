@@ -0,0 +1,194 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// defaultReconcileDebounce coalesces bursts of schema events (for example a
+// deploy that touches several federated services at once) into a single
+// reconcile pass.
+const defaultReconcileDebounce = 200 * time.Millisecond
+
+// SchemaEvent is implemented by SchemaAdded, SchemaChanged, and
+// SchemaRemoved.
+type SchemaEvent interface {
+	schemaEvent()
+}
+
+// SchemaAdded announces that a new federated service has come online, along
+// with the raw introspectionQueryResult JSON for its schema.
+type SchemaAdded struct {
+	Service             string
+	IntrospectionResult []byte
+}
+
+// SchemaChanged announces that an existing federated service's schema has
+// changed, along with its new raw introspectionQueryResult JSON.
+type SchemaChanged struct {
+	Service             string
+	IntrospectionResult []byte
+}
+
+// SchemaRemoved announces that a federated service has gone away and should
+// be dropped from the merged schema.
+type SchemaRemoved struct {
+	Service string
+}
+
+func (SchemaAdded) schemaEvent()   {}
+func (SchemaChanged) schemaEvent() {}
+func (SchemaRemoved) schemaEvent() {}
+
+// SchemaSubscriber is an optional, push-based extension of SchemaSyncer. If
+// a syncer passed to NewExecutor implements SchemaSubscriber, the executor
+// stops polling FetchPlanner on an interval and instead re-plans as events
+// arrive on the returned channel, debouncing bursts with a small window so a
+// rollout touching several services in quick succession triggers one
+// reconcile instead of many.
+type SchemaSubscriber interface {
+	SchemaSyncer
+
+	// InitialSchemas returns the current per-service introspection results,
+	// used to seed the executor's view of the world before Subscribe's
+	// events start arriving. Without this, the executor starts from an
+	// empty view and the first event it ever sees (for whichever one
+	// service happens to change or get (re-)announced first, e.g. a
+	// fsnotify watcher that never reports files that already existed
+	// before it started watching) would wrongly look like the only
+	// federated service that exists.
+	InitialSchemas(ctx context.Context) (map[string]*introspectionQueryResult, SyncDiagnostics, error)
+
+	// Subscribe starts streaming schema events. The returned channel is
+	// closed when ctx is canceled.
+	Subscribe(ctx context.Context) (<-chan SchemaEvent, error)
+}
+
+// runSubscription applies incoming schema events to the executor's view of
+// each service's schema and, after a quiet period, hands a snapshot off to
+// the reconciler worker pool.
+func (e *Executor) runSubscription(ctx context.Context, events <-chan SchemaEvent) {
+	var timer *time.Timer
+	var timerC <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			e.applyEvent(event)
+
+			if timer == nil {
+				timer = time.NewTimer(defaultReconcileDebounce)
+			} else {
+				if !timer.Stop() {
+					select {
+					case <-timer.C:
+					default:
+					}
+				}
+				timer.Reset(defaultReconcileDebounce)
+			}
+			timerC = timer.C
+
+		case <-timerC:
+			e.enqueueReconcile()
+			timerC = nil
+		}
+	}
+}
+
+// applyEvent updates the executor's cached per-service introspection
+// results in response to a single SchemaEvent. A malformed payload doesn't
+// touch the cached schema for that service (so a bad event can't regress a
+// service that was previously syncing fine), but it's recorded as a
+// SyncDiagnostic and logged rather than silently dropped, the same way a
+// bad fetch or parse is on the polling/gRPC paths.
+func (e *Executor) applyEvent(event SchemaEvent) {
+	e.schemaMu.Lock()
+	defer e.schemaMu.Unlock()
+
+	switch ev := event.(type) {
+	case SchemaAdded:
+		e.applyIntrospectionEvent(ev.Service, ev.IntrospectionResult)
+	case SchemaChanged:
+		e.applyIntrospectionEvent(ev.Service, ev.IntrospectionResult)
+	case SchemaRemoved:
+		delete(e.serviceSchemas, ev.Service)
+	}
+}
+
+// applyIntrospectionEvent decodes a SchemaAdded/SchemaChanged payload for
+// service and stores it, or records why it couldn't. Callers must hold
+// schemaMu.
+func (e *Executor) applyIntrospectionEvent(service string, introspectionResult []byte) {
+	var iq introspectionQueryResult
+	if err := json.Unmarshal(introspectionResult, &iq); err != nil {
+		diag := SyncDiagnostic{
+			Severity: SeverityError,
+			Service:  service,
+			Phase:    PhaseParse,
+			Summary:  "failed to parse pushed introspection result",
+			Detail:   err.Error(),
+		}
+		log.Printf("federation: %s: %s: %v", service, diag.Summary, err)
+		e.recordDiagnostics(append(e.LastSyncDiagnostics(), diag))
+		return
+	}
+	e.serviceSchemas[service] = &iq
+}
+
+// enqueueReconcile hands the latest snapshot of serviceSchemas to the
+// reconciler worker pool, dropping any still-pending snapshot in favor of
+// the fresher one.
+func (e *Executor) enqueueReconcile() {
+	e.schemaMu.Lock()
+	snapshot := make(map[string]*introspectionQueryResult, len(e.serviceSchemas))
+	for service, iq := range e.serviceSchemas {
+		snapshot[service] = iq
+	}
+	e.schemaMu.Unlock()
+
+	select {
+	case e.reconcileCh <- snapshot:
+	default:
+		select {
+		case <-e.reconcileCh:
+		default:
+		}
+		select {
+		case e.reconcileCh <- snapshot:
+		default:
+		}
+	}
+}
+
+// reconcileWorker is one member of the reconciler worker pool: it serially
+// converts snapshots into a merged schema and planner, and atomically swaps
+// the result in as the executor's current planner.
+func (e *Executor) reconcileWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case snapshot := <-e.reconcileCh:
+			planner, diags, err := planFromSchemas(snapshot, e.selector, nil)
+			if planner == nil {
+				e.recordDiagnostics(diags)
+				log.Printf("federation: reconcile failed, keeping previous planner: %v", err)
+				continue
+			}
+			_, rejectDiags := e.adoptPlannerChecked(planner)
+			e.recordDiagnostics(append(diags, rejectDiags...))
+		}
+	}
+}
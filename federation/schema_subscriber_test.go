@@ -0,0 +1,88 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSubscriber is a minimal SchemaSubscriber whose Subscribe just relays
+// whatever's sent on events, for exercising NewExecutor's push-based sync
+// path without fsnotify.
+type fakeSubscriber struct {
+	initial map[string]*introspectionQueryResult
+	events  chan SchemaEvent
+}
+
+func (f *fakeSubscriber) FetchPlanner(ctx context.Context) (*Planner, SyncDiagnostics, error) {
+	return planFromSchemas(f.initial, nil, nil)
+}
+
+func (f *fakeSubscriber) InitialSchemas(ctx context.Context) (map[string]*introspectionQueryResult, SyncDiagnostics, error) {
+	return f.initial, nil, nil
+}
+
+func (f *fakeSubscriber) Subscribe(ctx context.Context) (<-chan SchemaEvent, error) {
+	return f.events, nil
+}
+
+// TestNewExecutorSeedsInitialSchemasForSubscriber guards against the
+// executor's push-based sync path starting from an empty serviceSchemas map:
+// an event for just one service must reconcile a planner that still knows
+// about every other service seeded via InitialSchemas, not just the one the
+// event happened to mention.
+func TestNewExecutorSeedsInitialSchemasForSubscriber(t *testing.T) {
+	ctx := context.Background()
+	execs, err := makeExecutors(map[string]*schemabuilder.Schema{
+		"schema1": buildTestSchema1(),
+		"schema2": buildTestSchema2(),
+	})
+	require.NoError(t, err)
+
+	initial := make(map[string]*introspectionQueryResult, len(execs))
+	raw := make(map[string][]byte, len(execs))
+	for service, exec := range execs {
+		result, err := fetchSchema(ctx, exec, nil)
+		require.NoError(t, err)
+		raw[service] = result.Result
+
+		var iq introspectionQueryResult
+		require.NoError(t, json.Unmarshal(result.Result, &iq))
+		initial[service] = &iq
+	}
+
+	events := make(chan SchemaEvent, 1)
+	e, err := NewExecutor(ctx, execs, &CustomExecutorArgs{
+		SchemaSyncer: &fakeSubscriber{initial: initial, events: events},
+	})
+	require.NoError(t, err)
+	defer e.Close()
+
+	// Only schema1 "changes"; if the executor's seeded view didn't already
+	// include schema2, this reconcile would merge a planner missing
+	// schema2 entirely.
+	events <- SchemaChanged{Service: "schema1", IntrospectionResult: raw["schema1"]}
+	time.Sleep(500 * time.Millisecond)
+
+	runAndValidateQueryResults(t, ctx, e, `{ s2root }`, `{ "s2root": "hello" }`)
+}
+
+// TestApplyEventRecordsDiagnosticOnMalformedPayload guards against a
+// malformed pushed introspection result being silently dropped: it should
+// show up in LastSyncDiagnostics so operators can see which backend pushed
+// a broken schema, the same as a bad fetch or parse on the polling path.
+func TestApplyEventRecordsDiagnosticOnMalformedPayload(t *testing.T) {
+	e := &Executor{serviceSchemas: make(map[string]*introspectionQueryResult)}
+
+	e.applyEvent(SchemaChanged{Service: "schema1", IntrospectionResult: []byte("not json")})
+
+	diags := e.LastSyncDiagnostics()
+	require.Len(t, diags, 1)
+	require.Equal(t, "schema1", diags[0].Service)
+	require.Equal(t, PhaseParse, diags[0].Phase)
+	require.Equal(t, SeverityError, diags[0].Severity)
+}
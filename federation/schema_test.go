@@ -0,0 +1,50 @@
+package federation
+
+import (
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBuildMergedSchemaBuildsUnions guards against a field whose return
+// type is a UNION (or INTERFACE) silently falling back to a bare
+// *graphql.Scalar, which would misrepresent that field's type to anything
+// introspecting the merged schema (GRPCSchemaSyncer, peers).
+func TestBuildMergedSchemaBuildsUnions(t *testing.T) {
+	types := map[string]*typeInfo{
+		"Foo": {
+			Kind:   "OBJECT",
+			Fields: map[string]*fieldInfo{"name": {Providers: []string{"s1"}, ReturnType: "String"}},
+		},
+		"Bar": {
+			Kind:   "OBJECT",
+			Fields: map[string]*fieldInfo{"id": {Providers: []string{"s1"}, ReturnType: "String"}},
+		},
+		"FooOrBar": {
+			Kind:          "UNION",
+			Fields:        map[string]*fieldInfo{},
+			PossibleTypes: []string{"Foo", "Bar"},
+		},
+		"Query": {
+			Kind:   "OBJECT",
+			Fields: map[string]*fieldInfo{"both": {Providers: []string{"s1"}, ReturnType: "FooOrBar"}},
+		},
+	}
+
+	schema, err := buildMergedSchema(types)
+	require.NoError(t, err)
+
+	field := schema.Query.Fields["both"]
+	require.NotNil(t, field)
+
+	union, ok := field.Type.(*graphql.Union)
+	require.Truef(t, ok, "expected both's type to be a *graphql.Union, got %T", field.Type)
+
+	names := make([]string, 0, len(union.Types))
+	for name := range union.Types {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"Foo", "Bar"}, names)
+}
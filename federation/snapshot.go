@@ -0,0 +1,14 @@
+package federation
+
+// plannerSnapshot is the atomic unit of schema state for an Executor: the
+// merged schema, the planner built from it, and the selector used to build
+// it, all captured together so a mid-flight sync can never mix an old
+// planner with a newer selector (or vice versa). generation increases by
+// one on every swap and is safe to log or compare across queries to tell
+// which schema produced a given plan.
+type plannerSnapshot struct {
+	types      *convertedSchema
+	planner    *Planner
+	selector   ServiceSelector
+	generation uint64
+}
@@ -0,0 +1,185 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/samsarahq/thunder/graphql/schemabuilder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Foo and Bar are the types planner_test.go and schema_syncer_test.go plan
+// and execute federated queries against: Foo and Bar are both split across
+// schema1/schema2 (or s1/s2), each owning some fields outright and sharing
+// "name"/"id" so the planner's stay-on-current-service optimization has
+// something to exercise.
+
+type Foo struct {
+	Key  string
+	Name string
+}
+
+type Bar struct {
+	Key string
+}
+
+// fooOrBar is how buildTestSchema1 represents the s1both union: a
+// schemabuilder object with exactly one of its embedded pointers set,
+// yielding a GraphQL union of Foo | Bar.
+type fooOrBar struct {
+	*Foo
+	*Bar
+}
+
+// buildTestSchema1 builds the "schema1"/"s1" service shared by
+// planner_test.go and schema_syncer_test.go.
+func buildTestSchema1() *schemabuilder.Schema {
+	foos := []*Foo{
+		{Key: "foo-1", Name: "jimbo"},
+		{Key: "foo-2", Name: "bob"},
+	}
+	bars := map[string]*Bar{
+		"foo-1": {Key: "foo-1"},
+	}
+
+	schema := schemabuilder.NewSchema()
+
+	query := schema.Query()
+	query.FieldFunc("s1fff", func() []*Foo {
+		return foos
+	})
+	query.FieldFunc("s1echo", func(args struct {
+		Foo  string
+		Pair struct {
+			A int64
+			B int64
+		}
+	}) string {
+		return args.Foo
+	})
+	query.FieldFunc("s1both", func(args struct{ Bar bool }) *fooOrBar {
+		if args.Bar {
+			return &fooOrBar{Bar: bars["foo-1"]}
+		}
+		return &fooOrBar{Foo: foos[0]}
+	})
+	query.FieldFunc(federationFieldPrefix+"Foo", func(args struct{ Keys []string }) []*Foo {
+		result := make([]*Foo, len(args.Keys))
+		for i, key := range args.Keys {
+			for _, f := range foos {
+				if f.Key == key {
+					result[i] = f
+					break
+				}
+			}
+		}
+		return result
+	})
+	query.FieldFunc(federationFieldPrefix+"Bar", func(args struct{ Keys []string }) []*Bar {
+		result := make([]*Bar, len(args.Keys))
+		for i, key := range args.Keys {
+			result[i] = bars[key]
+		}
+		return result
+	})
+
+	foo := schema.Object("Foo", Foo{})
+	foo.FieldFunc(federationKey, func(f *Foo) string { return f.Key })
+	foo.FieldFunc("name", func(f *Foo) string { return f.Name })
+	foo.FieldFunc("s1hmm", func(f *Foo) string { return f.Name + "!!!" })
+	foo.FieldFunc("s1nest", func(f *Foo) *Foo { return f })
+
+	bar := schema.Object("Bar", Bar{})
+	bar.FieldFunc(federationKey, func(b *Bar) string { return b.Key })
+	bar.FieldFunc("id", func(b *Bar) string { return b.Key })
+	bar.FieldFunc("s1baz", func(b *Bar) string { return "baz-" + b.Key })
+
+	return schema
+}
+
+// buildTestSchema2 builds the "schema2"/"s2" service shared by
+// planner_test.go and schema_syncer_test.go.
+func buildTestSchema2() *schemabuilder.Schema {
+	schema := schemabuilder.NewSchema()
+
+	query := schema.Query()
+	query.FieldFunc("s2root", func() string { return "hello" })
+	query.FieldFunc(federationFieldPrefix+"Foo", func(args struct{ Keys []string }) []*Foo {
+		result := make([]*Foo, len(args.Keys))
+		for i, key := range args.Keys {
+			result[i] = &Foo{Key: key}
+		}
+		return result
+	})
+	query.FieldFunc(federationFieldPrefix+"Bar", func(args struct{ Keys []string }) []*Bar {
+		result := make([]*Bar, len(args.Keys))
+		for i, key := range args.Keys {
+			result[i] = &Bar{Key: key}
+		}
+		return result
+	})
+
+	foo := schema.Object("Foo", Foo{})
+	foo.FieldFunc(federationKey, func(f *Foo) string { return f.Key })
+	foo.FieldFunc("name", func(f *Foo) string { return f.Name })
+	foo.FieldFunc("s2ok", func(f *Foo) bool { return true })
+	foo.FieldFunc("s2bar", func(f *Foo) *Bar { return &Bar{Key: f.Key} })
+	foo.FieldFunc("s2nest", func(f *Foo) *Foo { return f })
+
+	bar := schema.Object("Bar", Bar{})
+	bar.FieldFunc(federationKey, func(b *Bar) string { return b.Key })
+	bar.FieldFunc("id", func(b *Bar) string { return b.Key })
+
+	return schema
+}
+
+// makeExecutors builds a leaf Executor per entry in schemas, each wrapping
+// the schemabuilder.Schema's built *graphql.Schema directly, the way a real
+// federated service would be wired in as one of NewExecutor's Executors.
+func makeExecutors(schemas map[string]*schemabuilder.Schema) (map[string]*Executor, error) {
+	executors := make(map[string]*Executor, len(schemas))
+	for service, schema := range schemas {
+		built, err := schema.Build()
+		if err != nil {
+			return nil, err
+		}
+		executors[service] = NewLocalExecutor(built)
+	}
+	return executors, nil
+}
+
+// mustParse parses query into the SelectionSet a Plan's SelectionSet field
+// can be compared against.
+func mustParse(query string) *graphql.SelectionSet {
+	return graphql.MustParse(query, map[string]interface{}{}).SelectionSet
+}
+
+// runAndValidateQueryResults executes query against e and asserts its result
+// matches expected, compared as decoded JSON so field order doesn't matter.
+func runAndValidateQueryResults(t *testing.T, ctx context.Context, e *Executor, query string, expected string) {
+	t.Helper()
+
+	value, err := e.Execute(ctx, mustParse(query))
+	require.NoError(t, err)
+
+	actual, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	var actualJSON, expectedJSON interface{}
+	require.NoError(t, json.Unmarshal(actual, &actualJSON))
+	require.NoError(t, json.Unmarshal([]byte(expected), &expectedJSON))
+	assert.Equal(t, expectedJSON, actualJSON)
+}
+
+// runAndValidateQueryError executes query against e and asserts it fails
+// with an error containing errSubstring.
+func runAndValidateQueryError(t *testing.T, ctx context.Context, e *Executor, query string, expected string, errSubstring string) {
+	t.Helper()
+
+	_, err := e.Execute(ctx, mustParse(query))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), errSubstring)
+}
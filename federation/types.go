@@ -0,0 +1,115 @@
+package federation
+
+import (
+	"github.com/samsarahq/thunder/graphql"
+	"github.com/samsarahq/thunder/graphql/introspection"
+)
+
+// introspectionQueryResult is the raw decoded result of running the standard
+// GraphQL introspection query against a federated service. It is the
+// exchange format between a SchemaSyncer and the planner: syncers fetch or
+// receive these bytes, unmarshal them into this struct, and hand a
+// map[string]*introspectionQueryResult (keyed by service name) to
+// convertSchema.
+type introspectionQueryResult struct {
+	Schema introspection.Schema `json:"__schema"`
+}
+
+// fieldInfo is the merged, planner-ready view of a single type's field: who
+// can resolve it and what it returns.
+type fieldInfo struct {
+	// Providers lists every service that declares this field on this type,
+	// in sorted (and so, deterministic "first owner") order.
+	Providers []string
+
+	// ReturnType is the named GraphQL type this field resolves to, with any
+	// List/NonNull wrapping already stripped off: selection sets are planned
+	// against the named type regardless of how it's wrapped.
+	ReturnType string
+}
+
+// typeInfo is the merged, planner-ready view of a single GraphQL type.
+type typeInfo struct {
+	Kind   string
+	Fields map[string]*fieldInfo
+
+	// PossibleTypes lists the concrete object types a Union or Interface can
+	// resolve to at runtime, used to build the merged schema's unions; the
+	// planner itself does not check fragment conditions against it.
+	PossibleTypes []string
+}
+
+// convertedSchema is the merged, planner-ready representation of every
+// federated service's schema.
+type convertedSchema struct {
+	// Schema is the merged schema in graphql.Schema form (its resolvers only
+	// return errors), built for introspection (e.g. BareIntrospectionSchema/RunIntrospectionQuery)
+	// and for exposing to peers; the planner itself walks types below
+	// instead, since it needs per-field provider lists graphql.Schema alone
+	// doesn't carry.
+	Schema *graphql.Schema
+
+	// Services records which services contributed to this merge, in a
+	// stable (sorted) order, so callers such as diagnostics or tracing can
+	// tell what went into a given snapshot.
+	Services []string
+
+	// types holds the merged per-type, per-field metadata (providers,
+	// return types, possible types) the planner recurses over.
+	types map[string]*typeInfo
+}
+
+// field returns the merged fieldInfo for typeName.fieldName, or nil if
+// either is unknown to the merged schema.
+func (c *convertedSchema) field(typeName, fieldName string) *fieldInfo {
+	t, ok := c.types[typeName]
+	if !ok {
+		return nil
+	}
+	return t.Fields[fieldName]
+}
+
+// owner returns the default service that resolves typeName.fieldName (the
+// first, alphabetically, to declare it), or "" if the field is not known to
+// the merged schema.
+func (c *convertedSchema) owner(typeName, fieldName string) string {
+	f := c.field(typeName, fieldName)
+	if f == nil || len(f.Providers) == 0 {
+		return ""
+	}
+	return f.Providers[0]
+}
+
+// provides reports whether service declares typeName.fieldName.
+func (c *convertedSchema) provides(typeName, fieldName, service string) bool {
+	f := c.field(typeName, fieldName)
+	if f == nil {
+		return false
+	}
+	for _, s := range f.Providers {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldType returns the named return type of typeName.fieldName, or "" if
+// either is unknown to the merged schema.
+func (c *convertedSchema) fieldType(typeName, fieldName string) string {
+	f := c.field(typeName, fieldName)
+	if f == nil {
+		return ""
+	}
+	return f.ReturnType
+}
+
+// possibleTypes returns the concrete object types typeName (a Union or
+// Interface) can resolve to.
+func (c *convertedSchema) possibleTypes(typeName string) []string {
+	t, ok := c.types[typeName]
+	if !ok {
+		return nil
+	}
+	return t.PossibleTypes
+}